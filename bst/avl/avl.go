@@ -11,14 +11,22 @@ type AVL[T any] struct {
 	root           *Node[T]
 	alloc          allocator.IAllocator[Node[T]]
 	cmp            compare.ICompare[T]
+	multiset       bool
+	epoch          uint64
+	modCount       uint64
+	safeIteration  bool
 	countableCheck bool
+	// created is the node allocated by the last newNode, InsertHandle reads it to avoid a second descent.
+	created *Node[T]
 }
 
 func New[T any](cmp compare.ICompare[T], opts ...OptionFunc[T]) *AVL[T] {
 	var opt = getOption(opts)
 	tree := &AVL[T]{
-		alloc: opt.alloc,
-		cmp:   cmp,
+		alloc:         opt.alloc,
+		cmp:           cmp,
+		multiset:      opt.multiset,
+		safeIteration: opt.safeIteration,
 	}
 
 	if tree.alloc == nil {
@@ -27,6 +35,7 @@ func New[T any](cmp compare.ICompare[T], opts ...OptionFunc[T]) *AVL[T] {
 	var init T
 	if _, ok := any(init).(bst.Countable); ok {
 		tree.countableCheck = true
+		tree.multiset = false
 	}
 	return tree
 }
@@ -34,6 +43,9 @@ func New[T any](cmp compare.ICompare[T], opts ...OptionFunc[T]) *AVL[T] {
 // Clear clears the AVL.
 func (t *AVL[T]) Clear() {
 	t.root = nil
+	t.epoch++
+	t.modCount++
+	t.created = nil
 	t.alloc.Release()
 }
 
@@ -50,8 +62,13 @@ func (t *AVL[T]) Size() int {
 // Insert inserts data into the AVL.
 // If data already exists, the data will be overwritten.
 // return the old data if data is overwritten, or the zero value.
+// In multiset mode, Insert adds an occurrence of data instead, and never overwrites the stored data.
 func (t *AVL[T]) Insert(data T) (old T, replaced bool) {
-	t.root = t.insert(t.root, data, func(n *Node[T]) {
+	t.root = t.insert(t.root, data, 1, func(n *Node[T]) {
+		if t.multiset {
+			n.count++
+			return
+		}
 		old = n.val
 		replaced = true
 		n.setVal(data, t.countableCheck)
@@ -63,7 +80,7 @@ func (t *AVL[T]) Insert(data T) (old T, replaced bool) {
 // If data already exists, the visit function f will be called instead.
 // It is guaranteed that f is called at most once.
 func (t *AVL[T]) InsertOrVisit(data T, f datastructure.VisitFunc[T]) {
-	t.root = t.insert(t.root, data, nodeVisitWrap(f))
+	t.root = t.insert(t.root, data, 1, nodeVisitWrap(f))
 }
 
 // InsertOrIgnore inserts data into the AVL.
@@ -71,7 +88,7 @@ func (t *AVL[T]) InsertOrVisit(data T, f datastructure.VisitFunc[T]) {
 // return true if the data is inserted successfully.
 func (t *AVL[T]) InsertOrIgnore(data T) (success bool) {
 	success = true
-	t.root = t.insert(t.root, data, func(n *Node[T]) {
+	t.root = t.insert(t.root, data, 1, func(n *Node[T]) {
 		success = false
 	})
 	return
@@ -80,11 +97,12 @@ func (t *AVL[T]) InsertOrIgnore(data T) (success bool) {
 // Delete deletes data from the treap.
 // If data does not exist, the operator is no effect.
 // return true if the data is deleted successfully.
+// In multiset mode, Delete removes an occurrence of data.
 func (t *AVL[T]) Delete(data T) (old T, success bool) {
 	t.root = t.delete(t.root, data, func(n *Node[T]) bool {
 		old = n.getValue()
 		success = true
-		return true
+		return t.removeOccurrence(n, 1)
 	})
 	return
 }
@@ -93,24 +111,34 @@ func (t *AVL[T]) Delete(data T) (old T, success bool) {
 // If data does not exist or f return false, the operator is no effect.
 // return true if the data exists and is deleted successfully.
 // It is guaranteed that f is called at most once.
+// In multiset mode, DeleteIf removes an occurrence of data.
 func (t *AVL[T]) DeleteIf(data T, f datastructure.ConditionFunc[T]) (success bool) {
 	t.root = t.delete(t.root, data, func(n *Node[T]) bool {
 		res := f(n.getValue())
 		success = res
-		return res
+		return res && t.removeOccurrence(n, 1)
 	})
 	return
 }
 
+// Compute computes the new data with the compute function f in a single descent.
+// If f returns keep true, the new data is stored, otherwise the existing data is deleted.
+// return the data in the AVL after the computation, and true if it exists.
+// In multiset mode, Compute removes an occurrence of data if f returns keep false.
+func (t *AVL[T]) Compute(data T, f datastructure.ComputeFunc[T]) (res T, exists bool) {
+	t.root = t.compute(t.root, data, f, &res, &exists)
+	return
+}
+
 func (t *AVL[T]) Find(data T) (res T, exists bool) {
 	enterLeft := func(root *Node[T]) bool {
-		return t.cmp.Compare(root.val, data).GT()
+		return t.compare(root.val, data).GT()
 	}
 	enterCur := func(root *Node[T]) bool {
-		return t.cmp.Compare(root.val, data).EQ()
+		return t.compare(root.val, data).EQ()
 	}
 	enterRight := func(root *Node[T]) bool {
-		return t.cmp.Compare(root.val, data).LT()
+		return t.compare(root.val, data).LT()
 	}
 	t.root.postorder(enterLeft, enterRight, enterCur, func(n *Node[T]) bool {
 		res = n.val
@@ -153,13 +181,13 @@ func (t *AVL[T]) Max() (res T, exists bool) {
 
 func (t *AVL[T]) Prev(data T) (res T, exists bool) {
 	enterRight := func(node *Node[T]) bool {
-		return t.cmp.Compare(node.getValue(), data).LT()
+		return t.compare(node.getValue(), data).LT()
 	}
 	enterLeft := func(node *Node[T]) bool {
-		return t.cmp.Compare(node.getValue(), data).GTE()
+		return t.compare(node.getValue(), data).GTE()
 	}
 	enterCur := func(node *Node[T]) bool {
-		return t.cmp.Compare(node.getValue(), data).LT()
+		return t.compare(node.getValue(), data).LT()
 	}
 	t.root.reversePostorder(
 		enterRight,
@@ -176,13 +204,13 @@ func (t *AVL[T]) Prev(data T) (res T, exists bool) {
 
 func (t *AVL[T]) Next(data T) (res T, exists bool) {
 	enterLeft := func(node *Node[T]) bool {
-		return t.cmp.Compare(node.getValue(), data).GT()
+		return t.compare(node.getValue(), data).GT()
 	}
 	enterRight := func(node *Node[T]) bool {
-		return t.cmp.Compare(node.getValue(), data).LTE()
+		return t.compare(node.getValue(), data).LTE()
 	}
 	enterCur := func(node *Node[T]) bool {
-		return t.cmp.Compare(node.getValue(), data).GT()
+		return t.compare(node.getValue(), data).GT()
 	}
 	t.root.postorder(
 		enterLeft,
@@ -199,15 +227,18 @@ func (t *AVL[T]) Next(data T) (res T, exists bool) {
 
 func (t *AVL[T]) FindOrNext(data T) (res T, exists bool) {
 	enterLeft := func(node *Node[T]) bool {
-		return t.cmp.Compare(node.getValue(), data).GT()
+		return t.compare(node.getValue(), data).GT()
 	}
 	enterRight := func(node *Node[T]) bool {
-		return t.cmp.Compare(node.getValue(), data).LT()
+		return t.compare(node.getValue(), data).LT()
+	}
+	enterCur := func(node *Node[T]) bool {
+		return t.compare(node.getValue(), data).GTE()
 	}
 	t.root.postorder(
 		enterLeft,
 		enterRight,
-		trueNodeConditionFunc[T],
+		enterCur,
 		func(n *Node[T]) bool {
 			res = n.val
 			exists = true
@@ -221,15 +252,18 @@ func (t *AVL[T]) FindOrNext(data T) (res T, exists bool) {
 // If no such element, return zero value and false.
 func (t *AVL[T]) FindOrPrev(data T) (res T, exists bool) {
 	enterRight := func(node *Node[T]) bool {
-		return t.cmp.Compare(node.getValue(), data).LT()
+		return t.compare(node.getValue(), data).LT()
 	}
 	enterLeft := func(node *Node[T]) bool {
-		return t.cmp.Compare(node.getValue(), data).GT()
+		return t.compare(node.getValue(), data).GT()
+	}
+	enterCur := func(node *Node[T]) bool {
+		return t.compare(node.getValue(), data).LTE()
 	}
 	t.root.reversePostorder(
 		enterRight,
 		enterLeft,
-		trueNodeConditionFunc[T],
+		enterCur,
 		func(n *Node[T]) bool {
 			res = n.val
 			exists = true
@@ -248,45 +282,70 @@ func (t *AVL[T]) RankNth(rank int) (res T, exists bool) {
 }
 
 func (t *AVL[T]) Range(f datastructure.ConditionFunc[T]) {
-	t.root.inorder(trueNodeConditionFunc[T], trueNodeConditionFunc[T], trueNodeConditionFunc[T], nodeConditionWrap(f))
+	t.iterate(bst.Unbounded[T](), bst.Unbounded[T](), nil, false, nodeConditionWrap(f))
 }
 
 func (t *AVL[T]) RangeS(start T, f datastructure.ConditionFunc[T]) {
-	enterLeft := func(root *Node[T]) bool {
-		return t.cmp.Compare(root.getValue(), start).GT()
-	}
-	enterCur := func(root *Node[T]) bool {
-		return t.cmp.Compare(root.getValue(), start).GTE()
-	}
-	t.root.inorder(enterLeft, enterCur, trueNodeConditionFunc[T], nodeConditionWrap[T](f))
+	t.iterate(bst.Included(start), bst.Unbounded[T](), nil, false, nodeConditionWrap(f))
 }
 
 func (t *AVL[T]) RangeSE(start, end T, f datastructure.ConditionFunc[T]) {
-	enterLeft := func(root *Node[T]) bool {
-		return t.cmp.Compare(root.getValue(), start).GT()
-	}
-	enterCur := func(root *Node[T]) bool {
-		r1 := t.cmp.Compare(root.getValue(), start)
-		r2 := t.cmp.Compare(root.getValue(), end)
-		return r1.GTE() && r2.LT()
+	t.iterate(bst.Included(start), bst.Excluded(end), nil, false, nodeConditionWrap(f))
+}
+
+func (t *AVL[T]) RangeE(end T, f datastructure.ConditionFunc[T]) {
+	t.iterate(bst.Unbounded[T](), bst.Excluded(end), nil, false, nodeConditionWrap(f))
+}
+
+// RangeBounds iterate over all elements E in the AVL that are in the range from lo to hi in ascending order.
+// The iteration will be interrupted if f returns false.
+func (t *AVL[T]) RangeBounds(lo, hi bst.Bound[T], f datastructure.ConditionFunc[T]) {
+	t.iterate(lo, hi, nil, false, nodeConditionWrap(f))
+}
+
+// All return an iterator over all elements in the AVL in ascending order.
+func (t *AVL[T]) All() datastructure.Seq[T] {
+	return func(yield func(T) bool) {
+		t.Range(yield)
 	}
-	enterRight := func(root *Node[T]) bool {
-		return t.cmp.Compare(root.getValue(), end).LT()
+}
+
+// Backward return an iterator over all elements in the AVL in descending order.
+func (t *AVL[T]) Backward() datastructure.Seq[T] {
+	return func(yield func(T) bool) {
+		t.iterate(bst.Unbounded[T](), bst.Unbounded[T](), nil, true, nodeConditionWrap(yield))
 	}
-	t.root.inorder(enterLeft, enterCur, enterRight, nodeConditionWrap[T](f))
 }
 
-func (t *AVL[T]) RangeE(end T, f datastructure.ConditionFunc[T]) {
-	enter := func(root *Node[T]) bool {
-		return t.cmp.Compare(root.getValue(), end).LT()
+// Between return an iterator over all elements E in the AVL that satisfy from <= E < to in ascending order.
+func (t *AVL[T]) Between(from, to T) datastructure.Seq[T] {
+	return func(yield func(T) bool) {
+		t.RangeSE(from, to, yield)
 	}
-	t.root.inorder(trueNodeConditionFunc[T], enter, enter, nodeConditionWrap[T](f))
+}
+
+// Comparator return the compare.ICompare that orders the elements of the AVL.
+func (t *AVL[T]) Comparator() compare.ICompare[T] {
+	return t.cmp
 }
 
 // Private method
 
-func (t *AVL[T]) newNode(data T) *Node[T] {
+// compare return the result of the comparator, it panics if the result is not one of EQ, LT and GT.
+func (t *AVL[T]) compare(a, b T) compare.Result {
+	r := t.cmp.Compare(a, b)
+	if !r.Valid() {
+		panic(compare.InvalidResult(a, b, r))
+	}
+	return r
+}
+
+func (t *AVL[T]) newNode(data T, count int) *Node[T] {
 	node := t.alloc.Allocate()
+	node.count = count
+	node.removed = false
+	t.modCount++
+	t.created = node
 	node.setVal(data, t.countableCheck)
 	node.height = 1
 	node.l = nil
@@ -318,9 +377,9 @@ func (t *AVL[T]) fixBalance(root *Node[T]) *Node[T] {
 	return root
 }
 
-func (t *AVL[T]) insert(root *Node[T], data T, f nodeVisitFunc[T]) *Node[T] {
+func (t *AVL[T]) insert(root *Node[T], data T, count int, f nodeVisitFunc[T]) *Node[T] {
 	if root == nil {
-		return t.newNode(data)
+		return t.newNode(data, count)
 	}
 	r := t.cmp.Compare(root.val, data)
 	switch r {
@@ -329,11 +388,11 @@ func (t *AVL[T]) insert(root *Node[T], data T, f nodeVisitFunc[T]) *Node[T] {
 			f(root)
 		}
 	case compare.GT:
-		root.l = t.insert(root.l, data, f)
+		root.l = t.insert(root.l, data, count, f)
 	case compare.LT:
-		root.r = t.insert(root.r, data, f)
+		root.r = t.insert(root.r, data, count, f)
 	default:
-		panic("impossible")
+		panic(compare.InvalidResult(root.val, data, r))
 	}
 	root.pushUp()
 	root = t.fixBalance(root)
@@ -352,6 +411,10 @@ func (t *AVL[T]) delete(root *Node[T], data T, f nodeConditionFunc[T]) *Node[T]
 		}
 		// make sure f is called only once
 		f = trueNodeConditionFunc[T]
+		if root.l == nil || root.r == nil {
+			root.removed = true
+			t.modCount++
+		}
 		if root.l == nil && root.r == nil {
 			return nil
 		} else if root.l != nil && root.r == nil {
@@ -375,7 +438,39 @@ func (t *AVL[T]) delete(root *Node[T], data T, f nodeConditionFunc[T]) *Node[T]
 	case compare.LT:
 		root.r = t.delete(root.r, data, f)
 	default:
-		panic("impossible")
+		panic(compare.InvalidResult(root.val, data, r))
+	}
+	root.pushUp()
+	root = t.fixBalance(root)
+	return root
+}
+
+func (t *AVL[T]) compute(root *Node[T], data T, f datastructure.ComputeFunc[T], res *T, exists *bool) *Node[T] {
+	if root == nil {
+		var zero T
+		if v, keep := f(zero, false); keep {
+			*res, *exists = v, true
+			return t.newNode(v, 1)
+		}
+		return nil
+	}
+	r := t.cmp.Compare(root.val, data)
+	switch r {
+	case compare.EQ:
+		if v, keep := f(root.getValue(), true); keep {
+			root.setVal(v, t.countableCheck)
+			*res, *exists = v, true
+		} else if !t.removeOccurrence(root, 1) {
+			*res, *exists = root.getValue(), true
+		} else {
+			return t.delete(root, data, nil)
+		}
+	case compare.GT:
+		root.l = t.compute(root.l, data, f, res, exists)
+	case compare.LT:
+		root.r = t.compute(root.r, data, f, res, exists)
+	default:
+		panic(compare.InvalidResult(root.val, data, r))
 	}
 	root.pushUp()
 	root = t.fixBalance(root)
@@ -410,6 +505,6 @@ func (t *AVL[T]) rank(root *Node[T], data T) int {
 	case compare.GT:
 		return t.rank(root.l, data)
 	default:
-		panic("impossible")
+		panic(compare.InvalidResult(root.val, data, result))
 	}
 }
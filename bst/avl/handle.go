@@ -0,0 +1,72 @@
+package avl
+
+// Handle refers to an element stored in the AVL,
+// it reads or replaces the element without searching the AVL again.
+// A Handle is invalidated when its element is deleted, moved by Update, or the AVL is cleared.
+// The zero value is an invalid Handle.
+type Handle[T any] struct {
+	tree  *AVL[T]
+	node  *Node[T]
+	epoch uint64
+}
+
+// Valid return true if the element of the handle is still in the AVL.
+func (h Handle[T]) Valid() bool {
+	return h.node != nil && !h.node.removed && h.tree.epoch == h.epoch
+}
+
+// Value return the element of the handle, and false if the handle is invalid.
+func (h Handle[T]) Value() (res T, valid bool) {
+	if !h.Valid() {
+		return
+	}
+	return h.node.getValue(), true
+}
+
+// Set replaces the element of the handle with data, whose compare-key must be EQ to the element.
+// return false if the handle is invalid or the compare-key is different.
+func (h Handle[T]) Set(data T) (success bool) {
+	if !h.Valid() || !h.tree.compare(h.node.val, data).EQ() {
+		return false
+	}
+	if h.tree.countableCheck {
+		// the count may change, re-enter the path to update the sizes
+		h.tree.root = h.tree.insert(h.tree.root, data, 1, func(n *Node[T]) {
+			n.setVal(data, true)
+		})
+		return true
+	}
+	h.node.setVal(data, false)
+	return true
+}
+
+// InsertHandle inserts data into the AVL like Insert, and return the handle of the stored element.
+func (t *AVL[T]) InsertHandle(data T) Handle[T] {
+	var node *Node[T]
+	t.root = t.insert(t.root, data, 1, func(n *Node[T]) {
+		node = n
+		if t.multiset {
+			n.count++
+			return
+		}
+		n.setVal(data, t.countableCheck)
+	})
+	if node == nil {
+		node = t.created
+	}
+	t.created = nil
+	return t.handle(node)
+}
+
+// FindHandle return the handle of the element that equals to data, and true if it exists.
+func (t *AVL[T]) FindHandle(data T) (h Handle[T], exists bool) {
+	node := t.findNode(data)
+	if node == nil {
+		return
+	}
+	return t.handle(node), true
+}
+
+func (t *AVL[T]) handle(node *Node[T]) Handle[T] {
+	return Handle[T]{tree: t, node: node, epoch: t.epoch}
+}
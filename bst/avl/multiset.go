@@ -0,0 +1,99 @@
+package avl
+
+import (
+	"github.com/Sora233/datastructure/bst"
+	"github.com/Sora233/datastructure/compare"
+)
+
+// Count return the number of occurrences of data in the AVL.
+// Without multiset mode, it is 1 if data exists, or 0.
+func (t *AVL[T]) Count(data T) int {
+	return t.findNode(data).getCount()
+}
+
+// Add adds n occurrences of data into the AVL, and return the count of data after adding,
+// and true if data did not exist before.
+// Without multiset mode, data is inserted if it does not exist, and the count is at most 1.
+// It panics if the elements implement bst.Countable.
+func (t *AVL[T]) Add(data T, n int) (count int, inserted bool) {
+	t.checkNotCountable("Add")
+	if n <= 0 {
+		return t.Count(data), false
+	}
+	if !t.multiset {
+		n = 1
+	}
+	count, inserted = n, true
+	t.root = t.insert(t.root, data, n, func(node *Node[T]) {
+		if t.multiset {
+			node.count += n
+		}
+		count, inserted = node.getCount(), false
+	})
+	return
+}
+
+// Remove removes at most n occurrences of data from the AVL.
+// return the number of removed occurrences, and true if data no longer exists.
+// Without multiset mode, data is deleted if n is positive.
+// It panics if the elements implement bst.Countable.
+func (t *AVL[T]) Remove(data T, n int) (removed int, deleted bool) {
+	t.checkNotCountable("Remove")
+	if n <= 0 {
+		return
+	}
+	t.root = t.delete(t.root, data, func(node *Node[T]) bool {
+		removed = node.getCount()
+		deleted = t.removeOccurrence(node, n)
+		if !deleted {
+			removed = n
+		}
+		return deleted
+	})
+	return
+}
+
+// RangeCount iterate over all elements in the AVL in ascending order with their number of occurrences.
+// The iteration will be interrupted if f returns false.
+func (t *AVL[T]) RangeCount(f func(data T, count int) bool) {
+	t.iterate(bst.Unbounded[T](), bst.Unbounded[T](), nil, false, func(node *Node[T]) bool {
+		return f(node.val, node.getCount())
+	})
+}
+
+// checkNotCountable panics if the elements implement bst.Countable,
+// their counts are kept by the elements, so the occurrences can not be added or removed by the tree.
+func (t *AVL[T]) checkNotCountable(op string) {
+	if t.countableCheck {
+		panic("avl: " + op + " is not supported for bst.Countable elements")
+	}
+}
+
+// removeOccurrence removes n occurrences of node in multiset mode,
+// return true if the node should be deleted from the tree.
+func (t *AVL[T]) removeOccurrence(node *Node[T], n int) bool {
+	if t.multiset && node.count > n {
+		node.count -= n
+		return false
+	}
+	return true
+}
+
+// findNode return the node that is EQ to data, or nil.
+func (t *AVL[T]) findNode(data T) *Node[T] {
+	node := t.root
+	for node != nil {
+		r := t.cmp.Compare(node.val, data)
+		switch r {
+		case compare.EQ:
+			return node
+		case compare.GT:
+			node = node.l
+		case compare.LT:
+			node = node.r
+		default:
+			panic(compare.InvalidResult(node.val, data, r))
+		}
+	}
+	return nil
+}
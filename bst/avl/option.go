@@ -5,7 +5,9 @@ import (
 )
 
 type option[T any] struct {
-	alloc allocator.IAllocator[Node[T]]
+	alloc         allocator.IAllocator[Node[T]]
+	multiset      bool
+	safeIteration bool
 }
 
 type OptionFunc[T any] func(*option[T])
@@ -17,6 +19,25 @@ func WithAllocator[T any](alloc allocator.IAllocator[Node[T]]) OptionFunc[T] {
 	}
 }
 
+// WithMultiset make the tree keep the number of occurrences of each element,
+// Insert and Delete add and remove an occurrence instead of overwriting and deleting the element,
+// and Size, Rank and RankNth count every occurrence.
+// It has no effect on the elements that implement bst.Countable.
+func WithMultiset[T any]() OptionFunc[T] {
+	return func(o *option[T]) {
+		o.multiset = true
+	}
+}
+
+// WithSafeIteration make the iterations of the tree continue from the element after the last visited one
+// when the tree is structurally modified during the iteration, instead of panic with bst.ErrModifiedDuringIteration.
+// The elements inserted after the last visited one will be visited.
+func WithSafeIteration[T any]() OptionFunc[T] {
+	return func(o *option[T]) {
+		o.safeIteration = true
+	}
+}
+
 func getOption[T any](opts []OptionFunc[T]) *option[T] {
 	var opt = new(option[T])
 	for _, o := range opts {
@@ -0,0 +1,77 @@
+package avl
+
+import (
+	"github.com/Sora233/datastructure"
+	"github.com/Sora233/datastructure/bst"
+	"github.com/Sora233/datastructure/compare"
+)
+
+// FindBy return the element E in the AVL that probe(E) returns compare.EQ.
+// If no such element, return zero value and false.
+func (t *AVL[T]) FindBy(probe bst.Probe[T]) (res T, exists bool) {
+	node := t.root
+	for node != nil {
+		r := probe(node.val)
+		switch r {
+		case compare.EQ:
+			return node.getValue(), true
+		case compare.GT:
+			node = node.l
+		case compare.LT:
+			node = node.r
+		default:
+			panic(bst.InvalidProbeResult(node.val, r))
+		}
+	}
+	return
+}
+
+// LowerBoundBy return the minimum element E in the AVL that probe(E) does not return compare.LT.
+// If no such element, return zero value and false.
+func (t *AVL[T]) LowerBoundBy(probe bst.Probe[T]) (res T, exists bool) {
+	probe = checkedProbe(probe)
+	return t.boundBy(func(elem T) bool {
+		return probe(elem).GTE()
+	})
+}
+
+// UpperBoundBy return the minimum element E in the AVL that probe(E) returns compare.GT.
+// If no such element, return zero value and false.
+func (t *AVL[T]) UpperBoundBy(probe bst.Probe[T]) (res T, exists bool) {
+	probe = checkedProbe(probe)
+	return t.boundBy(func(elem T) bool {
+		return probe(elem).GT()
+	})
+}
+
+// RangeBy iterate over all elements E in the AVL that probe(E) returns compare.EQ in ascending order.
+// The iteration will be interrupted if f returns false.
+func (t *AVL[T]) RangeBy(probe bst.Probe[T], f datastructure.ConditionFunc[T]) {
+	t.iterate(bst.Unbounded[T](), bst.Unbounded[T](), checkedProbe(probe), false, nodeConditionWrap(f))
+}
+
+// boundBy return the minimum element E that after(E) returns true,
+// after must be false for a prefix of the elements and true for the rest.
+func (t *AVL[T]) boundBy(after func(elem T) bool) (res T, exists bool) {
+	node := t.root
+	for node != nil {
+		if after(node.val) {
+			res, exists = node.getValue(), true
+			node = node.l
+		} else {
+			node = node.r
+		}
+	}
+	return
+}
+
+// checkedProbe wraps probe, it panics if the result is not one of EQ, LT and GT.
+func checkedProbe[T any](probe bst.Probe[T]) bst.Probe[T] {
+	return func(elem T) compare.Result {
+		r := probe(elem)
+		if !r.Valid() {
+			panic(bst.InvalidProbeResult(elem, r))
+		}
+		return r
+	}
+}
@@ -0,0 +1,49 @@
+package avl
+
+import (
+	"github.com/Sora233/datastructure"
+)
+
+// Update modifies the stored data in the AVL that equals to data with the modify function f.
+// If the compare-key is unchanged, the data is updated in place, otherwise it is moved to the new position,
+// overwriting the element that equals to the new data if there is one.
+// return true if the data exists and is updated.
+// In multiset mode, all occurrences of data are moved together, and are added to the element that equals to the new data.
+func (t *AVL[T]) Update(data T, f datastructure.ModifyFunc[T]) (success bool) {
+	node := t.findNode(data)
+	if node == nil {
+		return false
+	}
+	newData := f(node.getValue())
+	if t.compare(newData, node.val).EQ() {
+		if t.countableCheck {
+			// the count may change, re-enter the path to update the sizes
+			t.root = t.insert(t.root, newData, 1, func(n *Node[T]) {
+				n.setVal(newData, t.countableCheck)
+			})
+		} else {
+			node.setVal(newData, t.countableCheck)
+		}
+		return true
+	}
+	count := node.count
+	t.root = t.delete(t.root, node.val, nil)
+	t.root = t.insert(t.root, newData, count, func(n *Node[T]) {
+		if t.multiset {
+			n.count += count
+			return
+		}
+		n.setVal(newData, t.countableCheck)
+	})
+	return true
+}
+
+// Upsert inserts data into the AVL if it does not exist, otherwise modifies the stored data with f like Update.
+// return true if data is inserted.
+func (t *AVL[T]) Upsert(data T, f datastructure.ModifyFunc[T]) (inserted bool) {
+	if t.Update(data, f) {
+		return false
+	}
+	t.root = t.insert(t.root, data, 1, nil)
+	return true
+}
@@ -1,6 +1,17 @@
 package bst
 
-import "github.com/Sora233/datastructure"
+import (
+	"errors"
+
+	"github.com/Sora233/datastructure"
+	"github.com/Sora233/datastructure/compare"
+)
+
+// ErrModifiedDuringIteration is the panic value of the iterations of a tree
+// when the tree is structurally modified by the iteration callback,
+// that is an element is inserted into or deleted from the tree.
+// Replacing the value of an existing element is not a structural modification.
+var ErrModifiedDuringIteration = errors.New("bst: tree is structurally modified during iteration")
 
 // BinarySearchTree is the interface that wraps the basic operations of a binary search tree.
 type BinarySearchTree[T any] interface {
@@ -39,6 +50,26 @@ type BinarySearchTree[T any] interface {
 	// It is guaranteed that f is called at most once.
 	DeleteIf(data T, f datastructure.ConditionFunc[T]) (success bool)
 
+	// Compute computes the new data with the datastructure.ComputeFunc f in a single descent.
+	// f is called with the existing data and true, or with the zero value and false if data does not exist.
+	// If f returns keep true, the new data is stored, otherwise the existing data is deleted.
+	// return the data in the tree after the computation, and true if it exists.
+	// It is guaranteed that f is called exactly once.
+	Compute(data T, f datastructure.ComputeFunc[T]) (res T, exists bool)
+
+	// Update modifies the stored data that equals to data with the datastructure.ModifyFunc f.
+	// If the compare-key is unchanged, the data is updated in place, otherwise it is moved to the new position,
+	// overwriting the element that equals to the new data if there is one.
+	// If data does not exist, the operator is no effect.
+	// return true if the data exists and is updated.
+	// It is guaranteed that f is called at most once.
+	Update(data T, f datastructure.ModifyFunc[T]) (success bool)
+
+	// Upsert inserts data if it does not exist, otherwise modifies the stored data with f like Update.
+	// return true if data is inserted.
+	// It is guaranteed that f is called at most once.
+	Upsert(data T, f datastructure.ModifyFunc[T]) (inserted bool)
+
 	// Find return the data and true if the data exists in the tree.
 	// if the data doesn't exist, return the zero value and false.
 	Find(data T) (res T, exists bool)
@@ -46,6 +77,23 @@ type BinarySearchTree[T any] interface {
 	// Exists return true if the data exists in the tree.
 	Exists(data T) (exists bool)
 
+	// FindBy return the element E that probe(E) returns compare.EQ.
+	// If no such element, return zero value and false.
+	FindBy(probe Probe[T]) (res T, exists bool)
+
+	// LowerBoundBy return the minimum element E that probe(E) does not return compare.LT.
+	// If no such element, return zero value and false.
+	LowerBoundBy(probe Probe[T]) (res T, exists bool)
+
+	// UpperBoundBy return the minimum element E that probe(E) returns compare.GT.
+	// If no such element, return zero value and false.
+	UpperBoundBy(probe Probe[T]) (res T, exists bool)
+
+	// RangeBy iterate over all elements E that probe(E) returns compare.EQ in ascending order.
+	// The iteration will be interrupted if f returns false.
+	// The compare-key should not be modified during the iteration.
+	RangeBy(probe Probe[T], f datastructure.ConditionFunc[T])
+
 	// Min return the minimum element in the tree.
 	Min() (res T, exists bool)
 	// Max return the maximum element in the tree.
@@ -78,6 +126,9 @@ type BinarySearchTree[T any] interface {
 	// Range iterate over all elements in the tree in ascending order.
 	// The iteration will be interrupted if f returns false.
 	// The compare-key should not be modified during the iteration.
+	// If f inserts or deletes elements, Range panics with ErrModifiedDuringIteration,
+	// or continues from the element after the last visited one if the tree is created with safe iteration.
+	// The same applies to the other Range methods and the iterators.
 	Range(f datastructure.ConditionFunc[T])
 
 	// RangeS iterate over all elements E in the tree that satisfy E >= start in ascending order.
@@ -94,8 +145,59 @@ type BinarySearchTree[T any] interface {
 	// The iteration will be interrupted if f returns false.
 	// The compare-key should not be modified during the iteration.
 	RangeE(end T, f datastructure.ConditionFunc[T])
+
+	// RangeBounds iterate over all elements E in the tree that satisfy lo <= E <= hi in ascending order,
+	// whether each end is included is determined by the Bound.
+	// The iteration will be interrupted if f returns false.
+	// The compare-key should not be modified during the iteration.
+	RangeBounds(lo, hi Bound[T], f datastructure.ConditionFunc[T])
+
+	// All return an iterator over all elements in the tree in ascending order.
+	All() datastructure.Seq[T]
+
+	// Backward return an iterator over all elements in the tree in descending order.
+	Backward() datastructure.Seq[T]
+
+	// Between return an iterator over all elements E in the tree that satisfy from <= E < to in ascending order.
+	Between(from, to T) datastructure.Seq[T]
+
+	// Comparator return the compare.ICompare that orders the elements of the tree.
+	Comparator() compare.ICompare[T]
+}
+
+// Probe compares an element of the tree to the searched target without constructing a T,
+// it returns compare.LT if elem is ordered before the target, compare.GT if after, or compare.EQ if it matches.
+// The result must be monotonic in the order of the tree, e.g. a comparison on a prefix of the compare-key.
+type Probe[T any] func(elem T) compare.Result
+
+// InvalidProbeResult return the error for a Probe that returns r on elem, which is not one of EQ, LT and GT.
+func InvalidProbeResult(elem any, r compare.Result) *compare.InconsistentError {
+	return &compare.InconsistentError{A: elem, Result: r, Reason: "invalid probe result"}
 }
 
 type Countable interface {
 	Count() int
 }
+
+// MultisetTree is a BinarySearchTree that keeps the number of occurrences of each element,
+// the occurrences are counted by Size, Rank and RankNth.
+// If the elements implement Countable, the counts are kept by the elements themselves,
+// Add and Remove panic, use InsertOrVisit and DeleteIf to change the counts instead.
+type MultisetTree[T any] interface {
+	BinarySearchTree[T]
+
+	// Count return the number of occurrences of data.
+	Count(data T) int
+
+	// Add adds n occurrences of data, and return the count of data after adding,
+	// and true if data did not exist before.
+	Add(data T, n int) (count int, inserted bool)
+
+	// Remove removes at most n occurrences of data.
+	// return the number of removed occurrences, and true if data no longer exists.
+	Remove(data T, n int) (removed int, deleted bool)
+
+	// RangeCount iterate over all elements in the tree in ascending order with their number of occurrences.
+	// The iteration will be interrupted if f returns false.
+	RangeCount(f func(data T, count int) bool)
+}
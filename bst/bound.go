@@ -0,0 +1,85 @@
+package bst
+
+import "github.com/Sora233/datastructure/compare"
+
+type boundKind uint8
+
+const (
+	unbounded boundKind = iota
+	included
+	excluded
+)
+
+// Bound is an endpoint of a range of elements.
+// The zero value is Unbounded.
+type Bound[T any] struct {
+	key  T
+	kind boundKind
+}
+
+// Included return a Bound that includes key.
+func Included[T any](key T) Bound[T] {
+	return Bound[T]{key: key, kind: included}
+}
+
+// Excluded return a Bound that excludes key.
+func Excluded[T any](key T) Bound[T] {
+	return Bound[T]{key: key, kind: excluded}
+}
+
+// Unbounded return a Bound that does not limit the range.
+func Unbounded[T any]() Bound[T] {
+	return Bound[T]{}
+}
+
+// Key return the key of the bound, or the zero value if the bound is Unbounded.
+func (b Bound[T]) Key() T {
+	return b.key
+}
+
+// IsIncluded return true if the bound includes its key.
+func (b Bound[T]) IsIncluded() bool {
+	return b.kind == included
+}
+
+// IsExcluded return true if the bound excludes its key.
+func (b Bound[T]) IsExcluded() bool {
+	return b.kind == excluded
+}
+
+// IsUnbounded return true if the bound does not limit the range.
+func (b Bound[T]) IsUnbounded() bool {
+	return b.kind == unbounded
+}
+
+// IsLowerOf return true if data is in the range that starts from the bound.
+func (b Bound[T]) IsLowerOf(cmp compare.ICompare[T], data T) bool {
+	switch b.kind {
+	case included:
+		return cmp.Compare(data, b.key).GTE()
+	case excluded:
+		return cmp.Compare(data, b.key).GT()
+	default:
+		return true
+	}
+}
+
+// IsUpperOf return true if data is in the range that ends at the bound.
+func (b Bound[T]) IsUpperOf(cmp compare.ICompare[T], data T) bool {
+	switch b.kind {
+	case included:
+		return cmp.Compare(data, b.key).LTE()
+	case excluded:
+		return cmp.Compare(data, b.key).LT()
+	default:
+		return true
+	}
+}
+
+// MapBound return a Bound of the same kind whose key is converted by f.
+func MapBound[T any, U any](b Bound[T], f func(T) U) Bound[U] {
+	if b.kind == unbounded {
+		return Bound[U]{}
+	}
+	return Bound[U]{key: f(b.key), kind: b.kind}
+}
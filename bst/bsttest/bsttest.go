@@ -0,0 +1,718 @@
+// Package bsttest provides a conformance test suite for implementations of bst.BinarySearchTree.
+//
+// A tree that passes Run can be used with treemap.AsMap and treeset.AsSet.
+package bsttest
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/Sora233/datastructure"
+	"github.com/Sora233/datastructure/bst"
+	"github.com/Sora233/datastructure/compare"
+	"github.com/Sora233/datastructure/entry"
+)
+
+// Factory create an empty tree that orders the elements by cmp.
+type Factory[T any] func(cmp compare.ICompare[T]) bst.BinarySearchTree[T]
+
+// Element is the element type used by Run.
+// Elements are ordered by Key, Value is used to check which copy of an element is stored.
+type Element = entry.KV[int, int]
+
+// Run runs the conformance test suite against the trees created by factory.
+func Run(t *testing.T, factory Factory[Element]) {
+	newTree := func() bst.BinarySearchTree[Element] {
+		tree := factory(entry.OrderedKeyLessCompareF[int, int]())
+		if tree == nil {
+			t.Fatal("factory returned a nil tree")
+		}
+		return tree
+	}
+	t.Run("Empty", func(t *testing.T) { testEmpty(t, newTree()) })
+	t.Run("Insert", func(t *testing.T) { testInsert(t, newTree()) })
+	t.Run("InsertOrIgnore", func(t *testing.T) { testInsertOrIgnore(t, newTree()) })
+	t.Run("InsertOrVisit", func(t *testing.T) { testInsertOrVisit(t, newTree()) })
+	t.Run("Delete", func(t *testing.T) { testDelete(t, newTree()) })
+	t.Run("DeleteIf", func(t *testing.T) { testDeleteIf(t, newTree()) })
+	t.Run("Compute", func(t *testing.T) { testCompute(t, newTree()) })
+	t.Run("Update", func(t *testing.T) { testUpdate(t, newTree()) })
+	t.Run("Navigation", func(t *testing.T) { testNavigation(t, newTree()) })
+	t.Run("Rank", func(t *testing.T) { testRank(t, newTree()) })
+	t.Run("Range", func(t *testing.T) { testRange(t, newTree()) })
+	t.Run("Probe", func(t *testing.T) { testProbe(t, newTree()) })
+	t.Run("Iterator", func(t *testing.T) { testIterator(t, newTree()) })
+	t.Run("Modification", func(t *testing.T) { testModification(t, newTree()) })
+	t.Run("Clear", func(t *testing.T) { testClear(t, newTree()) })
+	t.Run("Random", func(t *testing.T) { testRandom(t, newTree()) })
+}
+
+func kv(key, value int) Element {
+	return entry.NewKV(key, value)
+}
+
+func key(key int) Element {
+	return entry.Key[int, int](key)
+}
+
+// model is the reference implementation that the tree is checked against.
+type model struct {
+	keys   []int
+	values map[int]int
+}
+
+func newModel() *model {
+	return &model{values: make(map[int]int)}
+}
+
+func (m *model) insert(e Element) (old Element, replaced bool) {
+	if v, found := m.values[e.Key]; found {
+		m.values[e.Key] = e.Value
+		return kv(e.Key, v), true
+	}
+	i := sort.SearchInts(m.keys, e.Key)
+	m.keys = append(m.keys, 0)
+	copy(m.keys[i+1:], m.keys[i:])
+	m.keys[i] = e.Key
+	m.values[e.Key] = e.Value
+	return
+}
+
+func (m *model) delete(k int) (old Element, success bool) {
+	v, found := m.values[k]
+	if !found {
+		return
+	}
+	i := sort.SearchInts(m.keys, k)
+	m.keys = append(m.keys[:i], m.keys[i+1:]...)
+	delete(m.values, k)
+	return kv(k, v), true
+}
+
+func (m *model) at(i int) (res Element, exists bool) {
+	if i < 0 || i >= len(m.keys) {
+		return
+	}
+	return kv(m.keys[i], m.values[m.keys[i]]), true
+}
+
+// lowerBound return the index of the first key >= k
+func (m *model) lowerBound(k int) int {
+	return sort.SearchInts(m.keys, k)
+}
+
+// upperBound return the index of the first key > k
+func (m *model) upperBound(k int) int {
+	return sort.SearchInts(m.keys, k+1)
+}
+
+func (m *model) elements(from, to int) []Element {
+	var res []Element
+	for i := from; i < to; i++ {
+		e, _ := m.at(i)
+		res = append(res, e)
+	}
+	return res
+}
+
+func checkResult(t *testing.T, op string, arg int, expected Element, expectedOk bool, actual Element, actualOk bool) {
+	t.Helper()
+	if expectedOk != actualOk {
+		t.Fatalf("%v(%v): expected exists=%v, got %v", op, arg, expectedOk, actualOk)
+	}
+	if expectedOk && expected != actual {
+		t.Fatalf("%v(%v): expected %v, got %v", op, arg, expected, actual)
+	}
+}
+
+func collect(f func(f datastructure.ConditionFunc[Element])) []Element {
+	var res []Element
+	f(func(e Element) bool {
+		res = append(res, e)
+		return true
+	})
+	return res
+}
+
+func checkElements(t *testing.T, op string, expected, actual []Element) {
+	t.Helper()
+	if len(expected) != len(actual) {
+		t.Fatalf("%v: expected %v elements %v, got %v elements %v", op, len(expected), expected, len(actual), actual)
+	}
+	for i := range expected {
+		if expected[i] != actual[i] {
+			t.Fatalf("%v: element %v mismatched, expected %v, got %v", op, i, expected[i], actual[i])
+		}
+	}
+}
+
+// checkTree compares every query of the tree with the model.
+func checkTree(t *testing.T, tree bst.BinarySearchTree[Element], m *model) {
+	t.Helper()
+	if tree.Size() != len(m.keys) {
+		t.Fatalf("Size: expected %v, got %v", len(m.keys), tree.Size())
+	}
+	if tree.Empty() != (len(m.keys) == 0) {
+		t.Fatalf("Empty: expected %v, got %v", len(m.keys) == 0, tree.Empty())
+	}
+	checkElements(t, "Range", m.elements(0, len(m.keys)), collect(tree.Range))
+
+	e, ok := m.at(0)
+	r, rok := tree.Min()
+	checkResult(t, "Min", 0, e, ok, r, rok)
+	e, ok = m.at(len(m.keys) - 1)
+	r, rok = tree.Max()
+	checkResult(t, "Max", 0, e, ok, r, rok)
+
+	lo, hi := -2, 2
+	if len(m.keys) > 0 {
+		lo, hi = m.keys[0]-2, m.keys[len(m.keys)-1]+2
+	}
+	for k := lo; k <= hi; k++ {
+		checkQuery(t, tree, m, k)
+	}
+}
+
+func checkQuery(t *testing.T, tree bst.BinarySearchTree[Element], m *model, k int) {
+	t.Helper()
+	lb, ub := m.lowerBound(k), m.upperBound(k)
+	_, found := m.values[k]
+
+	e, ok := m.at(lb)
+	ok = ok && found
+	r, rok := tree.Find(key(k))
+	checkResult(t, "Find", k, e, ok, r, rok)
+	if tree.Exists(key(k)) != found {
+		t.Fatalf("Exists(%v): expected %v", k, found)
+	}
+
+	e, ok = m.at(lb - 1)
+	r, rok = tree.Prev(key(k))
+	checkResult(t, "Prev", k, e, ok, r, rok)
+
+	e, ok = m.at(ub)
+	r, rok = tree.Next(key(k))
+	checkResult(t, "Next", k, e, ok, r, rok)
+
+	e, ok = m.at(lb)
+	r, rok = tree.FindOrNext(key(k))
+	checkResult(t, "FindOrNext", k, e, ok, r, rok)
+
+	e, ok = m.at(ub - 1)
+	r, rok = tree.FindOrPrev(key(k))
+	checkResult(t, "FindOrPrev", k, e, ok, r, rok)
+
+	if rank := tree.Rank(key(k)); rank != lb+1 {
+		t.Fatalf("Rank(%v): expected %v, got %v", k, lb+1, rank)
+	}
+}
+
+func testEmpty(t *testing.T, tree bst.BinarySearchTree[Element]) {
+	checkTree(t, tree, newModel())
+	if _, ok := tree.RankNth(1); ok {
+		t.Fatal("RankNth(1) on empty tree: expected not exists")
+	}
+	if _, ok := tree.Delete(key(1)); ok {
+		t.Fatal("Delete on empty tree: expected not exists")
+	}
+	called := false
+	tree.RangeS(key(0), func(Element) bool { called = true; return true })
+	tree.RangeE(key(0), func(Element) bool { called = true; return true })
+	tree.RangeSE(key(0), key(10), func(Element) bool { called = true; return true })
+	if called {
+		t.Fatal("Range on empty tree: expected callback not called")
+	}
+}
+
+func testInsert(t *testing.T, tree bst.BinarySearchTree[Element]) {
+	m := newModel()
+	for _, k := range []int{5, 3, 8, 1, 4, 7, 9, 2, 6} {
+		old, replaced := tree.Insert(kv(k, k))
+		if replaced {
+			t.Fatalf("Insert(%v): expected not replaced, got old %v", k, old)
+		}
+		m.insert(kv(k, k))
+		checkTree(t, tree, m)
+	}
+	for _, k := range []int{1, 5, 9} {
+		old, replaced := tree.Insert(kv(k, k*10))
+		if !replaced || old != kv(k, k) {
+			t.Fatalf("Insert(%v) on existing element: expected old %v, got %v, %v", k, kv(k, k), old, replaced)
+		}
+		m.insert(kv(k, k*10))
+		checkTree(t, tree, m)
+	}
+}
+
+func testInsertOrIgnore(t *testing.T, tree bst.BinarySearchTree[Element]) {
+	m := newModel()
+	for _, k := range []int{3, 1, 2} {
+		if !tree.InsertOrIgnore(kv(k, k)) {
+			t.Fatalf("InsertOrIgnore(%v): expected success", k)
+		}
+		m.insert(kv(k, k))
+	}
+	for _, k := range []int{3, 1, 2} {
+		if tree.InsertOrIgnore(kv(k, k*10)) {
+			t.Fatalf("InsertOrIgnore(%v) on existing element: expected no effect", k)
+		}
+	}
+	checkTree(t, tree, m)
+}
+
+func testInsertOrVisit(t *testing.T, tree bst.BinarySearchTree[Element]) {
+	m := newModel()
+	for _, k := range []int{2, 1, 3} {
+		tree.InsertOrVisit(kv(k, k), func(e Element) {
+			t.Fatalf("InsertOrVisit(%v): f called on absent element", k)
+		})
+		m.insert(kv(k, k))
+	}
+	for _, k := range []int{2, 1, 3} {
+		var visited []Element
+		tree.InsertOrVisit(kv(k, k*10), func(e Element) {
+			visited = append(visited, e)
+		})
+		if len(visited) != 1 || visited[0] != kv(k, k) {
+			t.Fatalf("InsertOrVisit(%v): expected f called once with %v, got %v", k, kv(k, k), visited)
+		}
+	}
+	checkTree(t, tree, m)
+}
+
+func testDelete(t *testing.T, tree bst.BinarySearchTree[Element]) {
+	m := newModel()
+	for k := 0; k < 32; k++ {
+		tree.Insert(kv(k, -k))
+		m.insert(kv(k, -k))
+	}
+	for _, k := range []int{0, 31, 15, 16, 15, 40, -1, 7, 8, 9, 0} {
+		e, ok := m.delete(k)
+		r, rok := tree.Delete(key(k))
+		checkResult(t, "Delete", k, e, ok, r, rok)
+		checkTree(t, tree, m)
+	}
+	for _, k := range append([]int(nil), m.keys...) {
+		tree.Delete(key(k))
+		m.delete(k)
+	}
+	checkTree(t, tree, m)
+}
+
+func testDeleteIf(t *testing.T, tree bst.BinarySearchTree[Element]) {
+	m := newModel()
+	for k := 0; k < 16; k++ {
+		tree.Insert(kv(k, k))
+		m.insert(kv(k, k))
+	}
+	for k := -1; k <= 16; k++ {
+		var calls []Element
+		success := tree.DeleteIf(key(k), func(e Element) bool {
+			calls = append(calls, e)
+			return false
+		})
+		if success {
+			t.Fatalf("DeleteIf(%v): expected no effect when f returns false", k)
+		}
+		e, ok := m.at(m.lowerBound(k))
+		if _, found := m.values[k]; !found {
+			if len(calls) != 0 {
+				t.Fatalf("DeleteIf(%v): f called on absent element", k)
+			}
+			continue
+		}
+		if len(calls) != 1 || !ok || calls[0] != e {
+			t.Fatalf("DeleteIf(%v): expected f called once with %v, got %v", k, e, calls)
+		}
+	}
+	checkTree(t, tree, m)
+	// delete every even key, including the nodes that have two children
+	for _, k := range []int{8, 4, 12, 0, 2, 6, 10, 14} {
+		var calls int
+		success := tree.DeleteIf(key(k), func(e Element) bool {
+			calls++
+			return e.Value%2 == 0
+		})
+		if !success || calls != 1 {
+			t.Fatalf("DeleteIf(%v): expected success with f called once, got %v with %v calls", k, success, calls)
+		}
+		m.delete(k)
+		checkTree(t, tree, m)
+	}
+}
+
+func testCompute(t *testing.T, tree bst.BinarySearchTree[Element]) {
+	r := rand.New(rand.NewSource(20231019))
+	m := newModel()
+	for i := 0; i < 5000; i++ {
+		k := r.Intn(100)
+		keep := r.Intn(3) != 0
+		expectedOld, expectedExists := m.values[k]
+		var calls int
+		res, exists := tree.Compute(key(k), func(old Element, found bool) (Element, bool) {
+			calls++
+			if found != expectedExists || (found && old != kv(k, expectedOld)) {
+				t.Fatalf("Compute(%v): expected f called with %v, %v, got %v, %v", k, kv(k, expectedOld), expectedExists, old, found)
+			}
+			return kv(k, old.Value+1), keep
+		})
+		if calls != 1 {
+			t.Fatalf("Compute(%v): expected f called once, got %v", k, calls)
+		}
+		if keep {
+			m.insert(kv(k, expectedOld+1))
+			checkResult(t, "Compute", k, kv(k, expectedOld+1), true, res, exists)
+		} else {
+			m.delete(k)
+			checkResult(t, "Compute", k, Element{}, false, res, exists)
+		}
+		if i%500 == 0 {
+			checkTree(t, tree, m)
+		}
+	}
+	checkTree(t, tree, m)
+}
+
+func testUpdate(t *testing.T, tree bst.BinarySearchTree[Element]) {
+	r := rand.New(rand.NewSource(20231019))
+	m := newModel()
+	for k := 0; k < 50; k++ {
+		tree.Insert(kv(k, k))
+		m.insert(kv(k, k))
+	}
+	for i := 0; i < 3000; i++ {
+		k := r.Intn(60)
+		newKey := k
+		if r.Intn(2) == 0 {
+			newKey = r.Intn(60)
+		}
+		newValue := r.Int()
+		var calls int
+		modify := func(old Element) Element {
+			calls++
+			if expected := kv(k, m.values[k]); old != expected {
+				t.Fatalf("Update(%v): expected f called with %v, got %v", k, expected, old)
+			}
+			return kv(newKey, newValue)
+		}
+		_, exists := m.values[k]
+		if r.Intn(2) == 0 {
+			if success := tree.Update(key(k), modify); success != exists {
+				t.Fatalf("Update(%v): expected %v, got %v", k, exists, success)
+			}
+		} else {
+			if inserted := tree.Upsert(kv(k, -1), modify); inserted == exists {
+				t.Fatalf("Upsert(%v): expected inserted %v, got %v", k, !exists, inserted)
+			}
+			if !exists {
+				m.insert(kv(k, -1))
+			}
+		}
+		if exists {
+			m.delete(k)
+			m.insert(kv(newKey, newValue))
+			if calls != 1 {
+				t.Fatalf("Update(%v): expected f called once, got %v", k, calls)
+			}
+		} else if calls != 0 {
+			t.Fatalf("Update(%v): f called on absent element", k)
+		}
+		if i%300 == 0 {
+			checkTree(t, tree, m)
+		}
+	}
+	checkTree(t, tree, m)
+}
+
+func testNavigation(t *testing.T, tree bst.BinarySearchTree[Element]) {
+	m := newModel()
+	tree.Insert(kv(10, 10))
+	m.insert(kv(10, 10))
+	checkTree(t, tree, m)
+	for _, k := range []int{20, 30, 40, 50} {
+		tree.Insert(kv(k, k))
+		m.insert(kv(k, k))
+	}
+	checkTree(t, tree, m)
+}
+
+func testRank(t *testing.T, tree bst.BinarySearchTree[Element]) {
+	m := newModel()
+	for k := 0; k < 64; k += 2 {
+		tree.Insert(kv(k, k))
+		m.insert(kv(k, k))
+	}
+	checkTree(t, tree, m)
+	for rank := -1; rank <= len(m.keys)+1; rank++ {
+		e, ok := m.at(rank - 1)
+		r, rok := tree.RankNth(rank)
+		checkResult(t, "RankNth", rank, e, ok, r, rok)
+	}
+}
+
+func testRange(t *testing.T, tree bst.BinarySearchTree[Element]) {
+	m := newModel()
+	for k := 0; k < 20; k += 2 {
+		tree.Insert(kv(k, k))
+		m.insert(kv(k, k))
+	}
+	for s := -1; s <= 20; s++ {
+		checkElements(t, "RangeS", m.elements(m.lowerBound(s), len(m.keys)), collect(func(f datastructure.ConditionFunc[Element]) {
+			tree.RangeS(key(s), f)
+		}))
+		checkElements(t, "RangeE", m.elements(0, m.lowerBound(s)), collect(func(f datastructure.ConditionFunc[Element]) {
+			tree.RangeE(key(s), f)
+		}))
+		for e := s; e <= 21; e++ {
+			checkElements(t, "RangeSE", m.elements(m.lowerBound(s), m.lowerBound(e)), collect(func(f datastructure.ConditionFunc[Element]) {
+				tree.RangeSE(key(s), key(e), f)
+			}))
+		}
+	}
+	type bound struct {
+		bst.Bound[Element]
+		// admits return true if k is in the range of the bound, as a lower bound when lower is true.
+		admits func(k int, lower bool) bool
+	}
+	bounds := func(b int) []bound {
+		return []bound{
+			{bst.Included(key(b)), func(k int, lower bool) bool { return k == b || (k > b) == lower }},
+			{bst.Excluded(key(b)), func(k int, lower bool) bool { return k != b && (k > b) == lower }},
+			{bst.Unbounded[Element](), func(int, bool) bool { return true }},
+		}
+	}
+	for s := -1; s <= 21; s++ {
+		for e := s - 1; e <= 21; e++ {
+			for _, lo := range bounds(s) {
+				for _, hi := range bounds(e) {
+					var expected []Element
+					for _, k := range m.keys {
+						if lo.admits(k, true) && hi.admits(k, false) {
+							expected = append(expected, kv(k, m.values[k]))
+						}
+					}
+					checkElements(t, "RangeBounds", expected, collect(func(f datastructure.ConditionFunc[Element]) {
+						tree.RangeBounds(lo.Bound, hi.Bound, f)
+					}))
+				}
+			}
+		}
+	}
+	for limit := 0; limit <= len(m.keys); limit++ {
+		var calls int
+		tree.Range(func(Element) bool {
+			calls++
+			return calls < limit
+		})
+		if expected := max(limit, 1); calls != expected {
+			t.Fatalf("Range: expected the iteration stops after %v calls, got %v", expected, calls)
+		}
+	}
+}
+
+func testProbe(t *testing.T, tree bst.BinarySearchTree[Element]) {
+	m := newModel()
+	for k := 0; k < 50; k += 3 {
+		tree.Insert(kv(k, -k))
+		m.insert(kv(k, -k))
+	}
+	for k := -1; k <= 51; k++ {
+		k := k
+		probe := func(e Element) compare.Result {
+			return compare.OrderedLessCompare(e.Key, k)
+		}
+		e, ok := m.at(m.lowerBound(k))
+		r, rok := tree.FindBy(probe)
+		checkResult(t, "FindBy", k, e, ok && e.Key == k, r, rok)
+		r, rok = tree.LowerBoundBy(probe)
+		checkResult(t, "LowerBoundBy", k, e, ok, r, rok)
+		e, ok = m.at(m.upperBound(k))
+		r, rok = tree.UpperBoundBy(probe)
+		checkResult(t, "UpperBoundBy", k, e, ok, r, rok)
+	}
+	// probe by a prefix of the key, every group of ten keys compares EQ
+	for g := -1; g <= 6; g++ {
+		g := g
+		probe := func(e Element) compare.Result {
+			return compare.OrderedLessCompare(e.Key/10, g)
+		}
+		checkElements(t, "RangeBy", m.elements(m.lowerBound(g*10), m.lowerBound(g*10+10)), collect(func(f datastructure.ConditionFunc[Element]) {
+			tree.RangeBy(probe, f)
+		}))
+	}
+}
+
+func testIterator(t *testing.T, tree bst.BinarySearchTree[Element]) {
+	m := newModel()
+	checkElements(t, "All", nil, collect(func(f datastructure.ConditionFunc[Element]) { tree.All()(f) }))
+	checkElements(t, "Backward", nil, collect(func(f datastructure.ConditionFunc[Element]) { tree.Backward()(f) }))
+	for k := 0; k < 20; k += 2 {
+		tree.Insert(kv(k, k))
+		m.insert(kv(k, k))
+	}
+	all := m.elements(0, len(m.keys))
+	checkElements(t, "All", all, collect(func(f datastructure.ConditionFunc[Element]) { tree.All()(f) }))
+	var backward []Element
+	for i := len(all) - 1; i >= 0; i-- {
+		backward = append(backward, all[i])
+	}
+	checkElements(t, "Backward", backward, collect(func(f datastructure.ConditionFunc[Element]) { tree.Backward()(f) }))
+	for s := -1; s <= 20; s++ {
+		for e := s; e <= 21; e++ {
+			checkElements(t, "Between", m.elements(m.lowerBound(s), m.lowerBound(e)), collect(func(f datastructure.ConditionFunc[Element]) {
+				tree.Between(key(s), key(e))(f)
+			}))
+		}
+	}
+	for limit := 1; limit <= len(m.keys); limit++ {
+		var calls int
+		tree.Backward()(func(e Element) bool {
+			if expected := backward[calls]; e != expected {
+				t.Fatalf("Backward: element %v mismatched, expected %v, got %v", calls, expected, e)
+			}
+			calls++
+			return calls < limit
+		})
+		if calls != limit {
+			t.Fatalf("Backward: expected the iteration stops after %v calls, got %v", limit, calls)
+		}
+	}
+}
+
+func testModification(t *testing.T, tree bst.BinarySearchTree[Element]) {
+	for k := 0; k < 20; k += 2 {
+		tree.Insert(kv(k, k))
+	}
+	ranges := map[string]func(f datastructure.ConditionFunc[Element]){
+		"Range":   tree.Range,
+		"RangeS":  func(f datastructure.ConditionFunc[Element]) { tree.RangeS(key(0), f) },
+		"RangeSE": func(f datastructure.ConditionFunc[Element]) { tree.RangeSE(key(0), key(20), f) },
+		"RangeE":  func(f datastructure.ConditionFunc[Element]) { tree.RangeE(key(20), f) },
+		"RangeBounds": func(f datastructure.ConditionFunc[Element]) {
+			tree.RangeBounds(bst.Unbounded[Element](), bst.Unbounded[Element](), f)
+		},
+		"RangeBy": func(f datastructure.ConditionFunc[Element]) {
+			tree.RangeBy(func(Element) compare.Result { return compare.EQ }, f)
+		},
+		"All":      func(f datastructure.ConditionFunc[Element]) { tree.All()(f) },
+		"Backward": func(f datastructure.ConditionFunc[Element]) { tree.Backward()(f) },
+		"Between":  func(f datastructure.ConditionFunc[Element]) { tree.Between(key(0), key(20))(f) },
+	}
+	for name, rangeFunc := range ranges {
+		// replacing the value of an existing element is not a structural modification
+		var calls int
+		rangeFunc(func(e Element) bool {
+			tree.Insert(kv(e.Key, e.Value+1))
+			calls++
+			return true
+		})
+		if calls != 10 {
+			t.Fatalf("%v: expected 10 calls when replacing values, got %v", name, calls)
+		}
+		checkModification(t, name+" Insert", tree, rangeFunc, func(e Element) {
+			tree.InsertOrIgnore(kv(e.Key+1, 0))
+		})
+		checkModification(t, name+" Delete", tree, rangeFunc, func(e Element) {
+			tree.Delete(e)
+		})
+		for k := 0; k < 20; k++ {
+			if k%2 == 0 {
+				tree.InsertOrIgnore(kv(k, k))
+			} else {
+				tree.Delete(key(k))
+			}
+		}
+		if tree.Size() != 10 {
+			t.Fatalf("%v: expected size 10 after restoring, got %v", name, tree.Size())
+		}
+	}
+}
+
+// checkModification calls modify on the first element visited by rangeFunc.
+// The iteration must either panic with bst.ErrModifiedDuringIteration, or complete in order without repeating an element
+// and visit every element that exists before and after the iteration.
+func checkModification(t *testing.T, op string, tree bst.BinarySearchTree[Element],
+	rangeFunc func(f datastructure.ConditionFunc[Element]), modify func(e Element)) {
+	before := collect(rangeFunc)
+	var visited []Element
+	r := func() (r any) {
+		defer func() {
+			r = recover()
+		}()
+		rangeFunc(func(e Element) bool {
+			visited = append(visited, e)
+			if len(visited) == 1 {
+				modify(e)
+			}
+			return true
+		})
+		return nil
+	}()
+	if r != nil {
+		if r != bst.ErrModifiedDuringIteration {
+			t.Fatalf("%v: expected panic %v, got %v", op, bst.ErrModifiedDuringIteration, r)
+		}
+		return
+	}
+	reverse := before[0].Key > before[len(before)-1].Key
+	for i := 1; i < len(visited); i++ {
+		if (visited[i-1].Key < visited[i].Key) == reverse {
+			t.Fatalf("%v: elements visited out of order or repeated: %v", op, visited)
+		}
+	}
+	seen := make(map[int]bool)
+	for _, e := range visited {
+		seen[e.Key] = true
+	}
+	for _, e := range before {
+		if tree.Exists(e) && !seen[e.Key] {
+			t.Fatalf("%v: element %v is skipped, visited %v", op, e, visited)
+		}
+	}
+}
+
+func testClear(t *testing.T, tree bst.BinarySearchTree[Element]) {
+	for k := 0; k < 100; k++ {
+		tree.Insert(kv(k, k))
+	}
+	tree.Clear()
+	checkTree(t, tree, newModel())
+	tree.Insert(kv(1, 1))
+	m := newModel()
+	m.insert(kv(1, 1))
+	checkTree(t, tree, m)
+}
+
+func testRandom(t *testing.T, tree bst.BinarySearchTree[Element]) {
+	r := rand.New(rand.NewSource(20231019))
+	m := newModel()
+	const maxKey = 200
+	for i := 0; i < 20000; i++ {
+		k := r.Intn(maxKey)
+		switch r.Intn(4) {
+		case 0, 1:
+			e := kv(k, r.Int())
+			expected, expectedOk := m.insert(e)
+			actual, actualOk := tree.Insert(e)
+			checkResult(t, "Insert", k, expected, expectedOk, actual, actualOk)
+		case 2:
+			expected, expectedOk := m.delete(k)
+			actual, actualOk := tree.Delete(key(k))
+			checkResult(t, "Delete", k, expected, expectedOk, actual, actualOk)
+		case 3:
+			checkQuery(t, tree, m, k)
+		}
+		if i%1000 == 0 {
+			checkTree(t, tree, m)
+		}
+	}
+	checkTree(t, tree, m)
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
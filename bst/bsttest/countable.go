@@ -0,0 +1,156 @@
+package bsttest
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/Sora233/datastructure/bst"
+	"github.com/Sora233/datastructure/entry"
+)
+
+// RunCountable runs the multiset conformance tests against the trees created by factory.
+// The elements implement bst.Countable, so every element contributes its Count to Size, Rank and RankNth.
+func RunCountable(t *testing.T, factory Factory[entry.Duplicate[int]]) {
+	newTree := func() bst.BinarySearchTree[entry.Duplicate[int]] {
+		tree := factory(entry.OrderedDuplicateLessCompareF[int]())
+		if tree == nil {
+			t.Fatal("factory returned a nil tree")
+		}
+		return tree
+	}
+	t.Run("Count", func(t *testing.T) { testCount(t, newTree()) })
+	t.Run("Random", func(t *testing.T) { testCountRandom(t, newTree()) })
+}
+
+type countModel struct {
+	keys   []int
+	counts map[int]int
+}
+
+func newCountModel() *countModel {
+	return &countModel{counts: make(map[int]int)}
+}
+
+func (m *countModel) add(k int, n int) {
+	if _, found := m.counts[k]; !found {
+		i := sort.SearchInts(m.keys, k)
+		m.keys = append(m.keys, 0)
+		copy(m.keys[i+1:], m.keys[i:])
+		m.keys[i] = k
+	}
+	m.counts[k] += n
+}
+
+func (m *countModel) sub(k int, n int) {
+	if _, found := m.counts[k]; !found {
+		return
+	}
+	m.counts[k] -= n
+	if m.counts[k] <= 0 {
+		i := sort.SearchInts(m.keys, k)
+		m.keys = append(m.keys[:i], m.keys[i+1:]...)
+		delete(m.counts, k)
+	}
+}
+
+func (m *countModel) total() int {
+	var res int
+	for _, c := range m.counts {
+		res += c
+	}
+	return res
+}
+
+func checkCountTree(t *testing.T, tree bst.BinarySearchTree[entry.Duplicate[int]], m *countModel) {
+	t.Helper()
+	if tree.Size() != m.total() {
+		t.Fatalf("Size: expected %v, got %v", m.total(), tree.Size())
+	}
+	var keys []int
+	tree.Range(func(d entry.Duplicate[int]) bool {
+		keys = append(keys, d.Key)
+		if d.Count() != m.counts[d.Key] {
+			t.Fatalf("Range: expected count of %v is %v, got %v", d.Key, m.counts[d.Key], d.Count())
+		}
+		return true
+	})
+	if len(keys) != len(m.keys) {
+		t.Fatalf("Range: expected %v, got %v", m.keys, keys)
+	}
+	for i := range keys {
+		if keys[i] != m.keys[i] {
+			t.Fatalf("Range: expected %v, got %v", m.keys, keys)
+		}
+	}
+	var smaller int
+	for _, k := range m.keys {
+		if rank := tree.Rank(entry.NewDuplicate(k)); rank != smaller+1 {
+			t.Fatalf("Rank(%v): expected %v, got %v", k, smaller+1, rank)
+		}
+		for i := 1; i <= m.counts[k]; i++ {
+			d, ok := tree.RankNth(smaller + i)
+			if !ok || d.Key != k {
+				t.Fatalf("RankNth(%v): expected %v, got %v, %v", smaller+i, k, d.Key, ok)
+			}
+		}
+		smaller += m.counts[k]
+	}
+	if _, ok := tree.RankNth(smaller + 1); ok {
+		t.Fatalf("RankNth(%v): expected not exists", smaller+1)
+	}
+}
+
+func testCount(t *testing.T, tree bst.BinarySearchTree[entry.Duplicate[int]]) {
+	m := newCountModel()
+	for _, k := range []int{3, 1, 2, 3, 3, 1} {
+		tree.InsertOrVisit(entry.NewDuplicate(k), entry.InsertDuplicate[int](1))
+		m.add(k, 1)
+		checkCountTree(t, tree, m)
+	}
+	tree.InsertOrVisit(entry.NewDuplicateCount(5, 4), entry.InsertDuplicate[int](4))
+	m.add(5, 4)
+	tree.InsertOrVisit(entry.NewDuplicateCount(5, 2), entry.InsertDuplicate[int](2))
+	m.add(5, 2)
+	checkCountTree(t, tree, m)
+	for _, k := range []int{3, 5, 1, 1, 4, 3} {
+		tree.DeleteIf(entry.NewDuplicate(k), entry.DeleteDuplicate[int](1))
+		m.sub(k, 1)
+		checkCountTree(t, tree, m)
+	}
+	tree.DeleteIf(entry.NewDuplicate(5), entry.DeleteDuplicate[int](5))
+	m.sub(5, 5)
+	checkCountTree(t, tree, m)
+	// replacing the element by Update keeps the sizes in sync with the new count
+	tree.Update(entry.NewDuplicate(2), func(old entry.Duplicate[int]) entry.Duplicate[int] {
+		return entry.NewDuplicateCount(old.Key, uint(old.Count()+5))
+	})
+	m.add(2, 5)
+	checkCountTree(t, tree, m)
+	tree.Update(entry.NewDuplicate(2), func(old entry.Duplicate[int]) entry.Duplicate[int] {
+		return entry.NewDuplicateCount(9, uint(old.Count()))
+	})
+	m.add(9, m.counts[2])
+	m.sub(2, m.counts[2])
+	checkCountTree(t, tree, m)
+}
+
+func testCountRandom(t *testing.T, tree bst.BinarySearchTree[entry.Duplicate[int]]) {
+	r := rand.New(rand.NewSource(20231019))
+	m := newCountModel()
+	for i := 0; i < 5000; i++ {
+		k := r.Intn(50)
+		n := r.Intn(3) + 1
+		if r.Intn(3) == 0 {
+			tree.DeleteIf(entry.NewDuplicate(k), entry.DeleteDuplicate[int](uint(n)))
+			m.sub(k, n)
+		} else {
+			tree.InsertOrVisit(entry.NewDuplicateCount(k, uint(n)), entry.InsertDuplicate[int](uint(n)))
+			m.add(k, n)
+		}
+		if i%250 == 0 {
+			checkCountTree(t, tree, m)
+		}
+	}
+	checkCountTree(t, tree, m)
+}
@@ -0,0 +1,196 @@
+package bsttest
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/Sora233/datastructure/bst"
+	"github.com/Sora233/datastructure/compare"
+)
+
+// MultisetFactory create an empty tree in multiset mode that orders the elements by cmp.
+type MultisetFactory[T any] func(cmp compare.ICompare[T]) bst.MultisetTree[T]
+
+// RunMultiset runs the multiset conformance tests against the trees created by factory.
+func RunMultiset(t *testing.T, factory MultisetFactory[int]) {
+	newTree := func() bst.MultisetTree[int] {
+		tree := factory(compare.OrderedLessCompareF[int]())
+		if tree == nil {
+			t.Fatal("factory returned a nil tree")
+		}
+		return tree
+	}
+	t.Run("InsertDelete", func(t *testing.T) { testMultisetInsertDelete(t, newTree()) })
+	t.Run("AddRemove", func(t *testing.T) { testMultisetAddRemove(t, newTree()) })
+	t.Run("Random", func(t *testing.T) { testMultisetRandom(t, newTree()) })
+}
+
+func checkMultisetTree(t *testing.T, tree bst.MultisetTree[int], m *countModel) {
+	t.Helper()
+	if tree.Size() != m.total() {
+		t.Fatalf("Size: expected %v, got %v", m.total(), tree.Size())
+	}
+	var keys, counts []int
+	tree.RangeCount(func(data int, count int) bool {
+		keys = append(keys, data)
+		counts = append(counts, count)
+		return true
+	})
+	if len(keys) != len(m.keys) {
+		t.Fatalf("RangeCount: expected %v, got %v", m.keys, keys)
+	}
+	for i := range keys {
+		if keys[i] != m.keys[i] || counts[i] != m.counts[keys[i]] {
+			t.Fatalf("RangeCount: expected %v with count %v, got %v with count %v", m.keys[i], m.counts[m.keys[i]], keys[i], counts[i])
+		}
+	}
+	var distinct int
+	tree.Range(func(int) bool {
+		distinct++
+		return true
+	})
+	if distinct != len(m.keys) {
+		t.Fatalf("Range: expected %v distinct elements, got %v", len(m.keys), distinct)
+	}
+	var smaller int
+	for _, k := range m.keys {
+		if c := tree.Count(k); c != m.counts[k] {
+			t.Fatalf("Count(%v): expected %v, got %v", k, m.counts[k], c)
+		}
+		if c := tree.Count(k + 1); m.counts[k+1] == 0 && c != 0 {
+			t.Fatalf("Count(%v): expected 0, got %v", k+1, c)
+		}
+		if rank := tree.Rank(k); rank != smaller+1 {
+			t.Fatalf("Rank(%v): expected %v, got %v", k, smaller+1, rank)
+		}
+		for i := 1; i <= m.counts[k]; i++ {
+			if d, ok := tree.RankNth(smaller + i); !ok || d != k {
+				t.Fatalf("RankNth(%v): expected %v, got %v, %v", smaller+i, k, d, ok)
+			}
+		}
+		smaller += m.counts[k]
+	}
+}
+
+func testMultisetInsertDelete(t *testing.T, tree bst.MultisetTree[int]) {
+	m := newCountModel()
+	for _, k := range []int{3, 1, 3, 2, 3, 1} {
+		if old, replaced := tree.Insert(k); replaced {
+			t.Fatalf("Insert(%v): expected not replaced, got old %v", k, old)
+		}
+		m.add(k, 1)
+		checkMultisetTree(t, tree, m)
+	}
+	if tree.InsertOrIgnore(3) {
+		t.Fatal("InsertOrIgnore(3) on existing element: expected no effect")
+	}
+	var calls int
+	tree.InsertOrVisit(1, func(int) { calls++ })
+	if calls != 1 {
+		t.Fatalf("InsertOrVisit(1): expected f called once, got %v", calls)
+	}
+	checkMultisetTree(t, tree, m)
+	for _, k := range []int{3, 3, 1, 4} {
+		_, expected := m.counts[k]
+		if _, ok := tree.Delete(k); ok != expected {
+			t.Fatalf("Delete(%v): expected %v, got %v", k, expected, ok)
+		}
+		m.sub(k, 1)
+		checkMultisetTree(t, tree, m)
+	}
+	if tree.DeleteIf(3, func(int) bool { return false }) {
+		t.Fatal("DeleteIf(3): expected no effect when f returns false")
+	}
+	if !tree.DeleteIf(3, func(int) bool { return true }) {
+		t.Fatal("DeleteIf(3): expected success")
+	}
+	m.sub(3, 1)
+	checkMultisetTree(t, tree, m)
+}
+
+func testMultisetAddRemove(t *testing.T, tree bst.MultisetTree[int]) {
+	m := newCountModel()
+	if c, inserted := tree.Add(5, 3); c != 3 || !inserted {
+		t.Fatalf("Add(5, 3): expected count 3 and inserted, got %v, %v", c, inserted)
+	}
+	if c, inserted := tree.Add(5, 2); c != 5 || inserted {
+		t.Fatalf("Add(5, 2): expected count 5 and not inserted, got %v, %v", c, inserted)
+	}
+	if c, inserted := tree.Add(5, 0); c != 5 || inserted {
+		t.Fatalf("Add(5, 0): expected count 5 and not inserted, got %v, %v", c, inserted)
+	}
+	tree.Add(1, 1)
+	m.add(5, 5)
+	m.add(1, 1)
+	checkMultisetTree(t, tree, m)
+	if removed, deleted := tree.Remove(5, 2); removed != 2 || deleted {
+		t.Fatalf("Remove(5, 2): expected 2, false, got %v, %v", removed, deleted)
+	}
+	m.sub(5, 2)
+	checkMultisetTree(t, tree, m)
+	if removed, deleted := tree.Remove(5, 10); removed != 3 || !deleted {
+		t.Fatalf("Remove(5, 10): expected 3, true, got %v, %v", removed, deleted)
+	}
+	m.sub(5, 10)
+	checkMultisetTree(t, tree, m)
+	if removed, deleted := tree.Remove(7, 1); removed != 0 || deleted {
+		t.Fatalf("Remove(7, 1): expected 0, false, got %v, %v", removed, deleted)
+	}
+	tree.Add(7, 2)
+	m.add(7, 2)
+	remove := func(old int, exists bool) (int, bool) { return old, false }
+	if res, exists := tree.Compute(7, remove); !exists || res != 7 {
+		t.Fatalf("Compute(7) with keep false: expected 7, true, got %v, %v", res, exists)
+	}
+	m.sub(7, 1)
+	checkMultisetTree(t, tree, m)
+	if _, exists := tree.Compute(7, remove); exists {
+		t.Fatal("Compute(7) with keep false on the last occurrence: expected not exists")
+	}
+	m.sub(7, 1)
+	checkMultisetTree(t, tree, m)
+
+	tree.Add(2, 3)
+	tree.Add(4, 2)
+	m.add(2, 3)
+	m.add(4, 2)
+	if !tree.Update(2, func(old int) int { return old + 2 }) {
+		t.Fatal("Update(2): expected success")
+	}
+	m.sub(2, 3)
+	m.add(4, 3)
+	checkMultisetTree(t, tree, m)
+	if tree.Upsert(4, func(old int) int { return old + 4 }) {
+		t.Fatal("Upsert(4) on existing element: expected not inserted")
+	}
+	m.sub(4, 5)
+	m.add(8, 5)
+	checkMultisetTree(t, tree, m)
+}
+
+func testMultisetRandom(t *testing.T, tree bst.MultisetTree[int]) {
+	r := rand.New(rand.NewSource(20231019))
+	m := newCountModel()
+	for i := 0; i < 5000; i++ {
+		k := r.Intn(50)
+		n := r.Intn(3) + 1
+		switch r.Intn(4) {
+		case 0:
+			tree.Insert(k)
+			m.add(k, 1)
+		case 1:
+			tree.Delete(k)
+			m.sub(k, 1)
+		case 2:
+			tree.Add(k, n)
+			m.add(k, n)
+		case 3:
+			tree.Remove(k, n)
+			m.sub(k, n)
+		}
+		if i%250 == 0 {
+			checkMultisetTree(t, tree, m)
+		}
+	}
+	checkMultisetTree(t, tree, m)
+}
@@ -0,0 +1,58 @@
+package treap
+
+import (
+	"github.com/Sora233/datastructure/bst"
+	"github.com/Sora233/datastructure/compare"
+)
+
+// iterate traversal the elements E in the Treap that satisfy lo <= E <= hi and probe(E) returns compare.EQ,
+// in descending order if reverse is true, otherwise in ascending order.
+// A nil probe admits all elements.
+// If the Treap is structurally modified by f, iterate panics with bst.ErrModifiedDuringIteration,
+// or continues from the element after the last visited one in safe iteration mode.
+func (t *Treap[T]) iterate(lo, hi bst.Bound[T], probe bst.Probe[T], reverse bool, f nodeConditionFunc[T]) {
+	cmp := compare.WithFunc[T](t.compare)
+	enterLeft := func(root *Node[T]) bool {
+		return (lo.IsUnbounded() || t.compare(root.getValue(), lo.Key()).GT()) &&
+			(probe == nil || probe(root.val).GTE())
+	}
+	enterCur := func(root *Node[T]) bool {
+		return lo.IsLowerOf(cmp, root.getValue()) && hi.IsUpperOf(cmp, root.getValue()) &&
+			(probe == nil || probe(root.val).EQ())
+	}
+	enterRight := func(root *Node[T]) bool {
+		return (hi.IsUnbounded() || t.compare(root.getValue(), hi.Key()).LT()) &&
+			(probe == nil || probe(root.val).LTE())
+	}
+	for {
+		modCount := t.modCount
+		modified := false
+		visit := func(node *Node[T]) bool {
+			last := node.getValue()
+			if !f(node) {
+				return false
+			}
+			if t.modCount != modCount {
+				modified = true
+				if reverse {
+					hi = bst.Excluded(last)
+				} else {
+					lo = bst.Excluded(last)
+				}
+				return false
+			}
+			return true
+		}
+		if reverse {
+			t.root.reverseInorder(enterRight, enterCur, enterLeft, visit)
+		} else {
+			t.root.inorder(enterLeft, enterCur, enterRight, visit)
+		}
+		if !modified {
+			return
+		}
+		if !t.safeIteration {
+			panic(bst.ErrModifiedDuringIteration)
+		}
+	}
+}
@@ -10,6 +10,8 @@ type Node[T any] struct {
 	l, r     *Node[T]
 	val      T
 	countval bst.Countable
+	count    int
+	removed  bool // removed is set when the node is deleted, it invalidates the handles of the node
 	priority int
 	size     int
 }
@@ -53,7 +55,7 @@ func (node *Node[T]) getCount() int {
 	if node.countval != nil {
 		return node.countval.Count()
 	}
-	return 1
+	return node.count
 }
 
 // leftRotate operator a left-rotate
@@ -5,8 +5,10 @@ import (
 )
 
 type option[T any] struct {
-	alloc allocator.IAllocator[Node[T]]
-	r     func() int
+	alloc         allocator.IAllocator[Node[T]]
+	multiset      bool
+	r             func() int
+	safeIteration bool
 }
 
 type OptionFunc[T any] func(*option[T])
@@ -26,6 +28,25 @@ func WithRand[T any](r func() int) OptionFunc[T] {
 	}
 }
 
+// WithMultiset make the tree keep the number of occurrences of each element,
+// Insert and Delete add and remove an occurrence instead of overwriting and deleting the element,
+// and Size, Rank and RankNth count every occurrence.
+// It has no effect on the elements that implement bst.Countable.
+func WithMultiset[T any]() OptionFunc[T] {
+	return func(o *option[T]) {
+		o.multiset = true
+	}
+}
+
+// WithSafeIteration make the iterations of the tree continue from the element after the last visited one
+// when the tree is structurally modified during the iteration, instead of panic with bst.ErrModifiedDuringIteration.
+// The elements inserted after the last visited one will be visited.
+func WithSafeIteration[T any]() OptionFunc[T] {
+	return func(o *option[T]) {
+		o.safeIteration = true
+	}
+}
+
 func getOption[T any](opts []OptionFunc[T]) *option[T] {
 	var opt = new(option[T])
 	for _, o := range opts {
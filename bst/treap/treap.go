@@ -13,8 +13,14 @@ type Treap[T any] struct {
 	root           *Node[T]
 	alloc          allocator.IAllocator[Node[T]]
 	cmp            compare.ICompare[T]
+	multiset       bool
+	epoch          uint64
+	modCount       uint64
+	safeIteration  bool
 	r              func() int
 	countableCheck bool
+	// created is the node allocated by the last newNode, InsertHandle reads it to avoid a second descent.
+	created *Node[T]
 }
 
 // New create a new treap
@@ -22,9 +28,11 @@ type Treap[T any] struct {
 func New[T any](cmp compare.ICompare[T], opts ...OptionFunc[T]) *Treap[T] {
 	var opt = getOption(opts)
 	tree := &Treap[T]{
-		alloc: opt.alloc,
-		cmp:   cmp,
-		r:     opt.r,
+		alloc:         opt.alloc,
+		cmp:           cmp,
+		multiset:      opt.multiset,
+		r:             opt.r,
+		safeIteration: opt.safeIteration,
 	}
 	if tree.r == nil {
 		tree.r = rand.Int
@@ -35,6 +43,7 @@ func New[T any](cmp compare.ICompare[T], opts ...OptionFunc[T]) *Treap[T] {
 	var init T
 	if _, ok := any(init).(bst.Countable); ok {
 		tree.countableCheck = true
+		tree.multiset = false
 	}
 	return tree
 }
@@ -42,6 +51,9 @@ func New[T any](cmp compare.ICompare[T], opts ...OptionFunc[T]) *Treap[T] {
 // Clear clears the treap.
 func (t *Treap[T]) Clear() {
 	t.root = nil
+	t.epoch++
+	t.modCount++
+	t.created = nil
 	t.alloc.Release()
 }
 
@@ -58,8 +70,13 @@ func (t *Treap[T]) Size() int {
 // Insert inserts data into the treap.
 // If data already exists, the data will be overwritten.
 // return the old data if data is overwritten, or the zero value.
+// In multiset mode, Insert adds an occurrence of data instead, and never overwrites the stored data.
 func (t *Treap[T]) Insert(data T) (old T, replaced bool) {
-	t.root = t.insert(t.root, data, func(n *Node[T]) {
+	t.root = t.insert(t.root, data, 1, func(n *Node[T]) {
+		if t.multiset {
+			n.count++
+			return
+		}
 		old = n.val
 		replaced = true
 		n.setVal(data, t.countableCheck)
@@ -71,7 +88,7 @@ func (t *Treap[T]) Insert(data T) (old T, replaced bool) {
 // If data already exists, the visit function f will be called instead.
 // It is guaranteed that f is called at most once.
 func (t *Treap[T]) InsertOrVisit(data T, f datastructure.VisitFunc[T]) {
-	t.root = t.insert(t.root, data, nodeVisitWrap(f))
+	t.root = t.insert(t.root, data, 1, nodeVisitWrap(f))
 }
 
 // InsertOrIgnore inserts data into the treap.
@@ -79,7 +96,7 @@ func (t *Treap[T]) InsertOrVisit(data T, f datastructure.VisitFunc[T]) {
 // return true if the data is inserted successfully.
 func (t *Treap[T]) InsertOrIgnore(data T) (success bool) {
 	success = true
-	t.root = t.insert(t.root, data, func(n *Node[T]) {
+	t.root = t.insert(t.root, data, 1, func(n *Node[T]) {
 		success = false
 	})
 	return
@@ -88,11 +105,12 @@ func (t *Treap[T]) InsertOrIgnore(data T) (success bool) {
 // Delete deletes data from the treap.
 // If data does not exist, the operator is no effect.
 // return true if the data is deleted successfully.
+// In multiset mode, Delete removes an occurrence of data.
 func (t *Treap[T]) Delete(data T) (old T, success bool) {
 	t.root = t.delete(t.root, data, func(n *Node[T]) bool {
 		old = n.getValue()
 		success = true
-		return true
+		return t.removeOccurrence(n, 1)
 	})
 	return
 }
@@ -101,15 +119,25 @@ func (t *Treap[T]) Delete(data T) (old T, success bool) {
 // If data does not exist or f return false, the operator is no effect.
 // return true if the data exists and is deleted successfully.
 // It is guaranteed that f is called at most once.
+// In multiset mode, DeleteIf removes an occurrence of data.
 func (t *Treap[T]) DeleteIf(data T, f datastructure.ConditionFunc[T]) (success bool) {
 	t.root = t.delete(t.root, data, func(n *Node[T]) bool {
 		result := f(n.getValue())
 		success = result
-		return result
+		return result && t.removeOccurrence(n, 1)
 	})
 	return
 }
 
+// Compute computes the new data with the compute function f in a single descent.
+// If f returns keep true, the new data is stored, otherwise the existing data is deleted.
+// return the data in the treap after the computation, and true if it exists.
+// In multiset mode, Compute removes an occurrence of data if f returns keep false.
+func (t *Treap[T]) Compute(data T, f datastructure.ComputeFunc[T]) (res T, exists bool) {
+	t.root = t.compute(t.root, data, f, &res, &exists)
+	return
+}
+
 // Rank return the rank of data in the treap.
 // if the rank of data is N, it means there are (N-1) elements is smaller than data
 func (t *Treap[T]) Rank(data T) int {
@@ -125,13 +153,13 @@ func (t *Treap[T]) RankNth(rank int) (T, bool) {
 // If no such element, return zero value and false.
 func (t *Treap[T]) Prev(data T) (res T, exists bool) {
 	enterRight := func(node *Node[T]) bool {
-		return t.cmp.Compare(node.getValue(), data).LT()
+		return t.compare(node.getValue(), data).LT()
 	}
 	enterLeft := func(node *Node[T]) bool {
-		return t.cmp.Compare(node.getValue(), data).GTE()
+		return t.compare(node.getValue(), data).GTE()
 	}
 	enterCur := func(node *Node[T]) bool {
-		return t.cmp.Compare(node.getValue(), data).LT()
+		return t.compare(node.getValue(), data).LT()
 	}
 	t.root.reversePostorder(
 		enterRight,
@@ -150,13 +178,13 @@ func (t *Treap[T]) Prev(data T) (res T, exists bool) {
 // If no such element, return zero value and false.
 func (t *Treap[T]) Next(data T) (res T, exists bool) {
 	enterLeft := func(node *Node[T]) bool {
-		return t.cmp.Compare(node.getValue(), data).GT()
+		return t.compare(node.getValue(), data).GT()
 	}
 	enterRight := func(node *Node[T]) bool {
-		return t.cmp.Compare(node.getValue(), data).LTE()
+		return t.compare(node.getValue(), data).LTE()
 	}
 	enterCur := func(node *Node[T]) bool {
-		return t.cmp.Compare(node.getValue(), data).GT()
+		return t.compare(node.getValue(), data).GT()
 	}
 	t.root.postorder(
 		enterLeft,
@@ -209,13 +237,13 @@ func (t *Treap[T]) Max() (res T, exists bool) {
 // If no such element, return zero value and false.
 func (t *Treap[T]) FindOrNext(data T) (res T, exists bool) {
 	enterLeft := func(node *Node[T]) bool {
-		return t.cmp.Compare(node.getValue(), data).GT()
+		return t.compare(node.getValue(), data).GT()
 	}
 	enterRight := func(node *Node[T]) bool {
-		return t.cmp.Compare(node.getValue(), data).LT()
+		return t.compare(node.getValue(), data).LT()
 	}
 	enterCur := func(node *Node[T]) bool {
-		return t.cmp.Compare(node.getValue(), data).GTE()
+		return t.compare(node.getValue(), data).GTE()
 	}
 	t.root.postorder(
 		enterLeft,
@@ -234,13 +262,13 @@ func (t *Treap[T]) FindOrNext(data T) (res T, exists bool) {
 // If no such element, return zero value and false.
 func (t *Treap[T]) FindOrPrev(data T) (res T, exists bool) {
 	enterRight := func(node *Node[T]) bool {
-		return t.cmp.Compare(node.getValue(), data).LT()
+		return t.compare(node.getValue(), data).LT()
 	}
 	enterLeft := func(node *Node[T]) bool {
-		return t.cmp.Compare(node.getValue(), data).GT()
+		return t.compare(node.getValue(), data).GT()
 	}
 	enterCur := func(node *Node[T]) bool {
-		return t.cmp.Compare(node.getValue(), data).LTE()
+		return t.compare(node.getValue(), data).LTE()
 	}
 	t.root.reversePostorder(
 		enterRight,
@@ -259,13 +287,13 @@ func (t *Treap[T]) FindOrPrev(data T) (res T, exists bool) {
 // if the data doesn't exist, return the zero value and false.
 func (t *Treap[T]) Find(data T) (res T, exists bool) {
 	enterLeft := func(root *Node[T]) bool {
-		return t.cmp.Compare(root.val, data).GT()
+		return t.compare(root.val, data).GT()
 	}
 	enterCur := func(root *Node[T]) bool {
-		return t.cmp.Compare(root.val, data).EQ()
+		return t.compare(root.val, data).EQ()
 	}
 	enterRight := func(root *Node[T]) bool {
-		return t.cmp.Compare(root.val, data).LT()
+		return t.compare(root.val, data).LT()
 	}
 	t.root.postorder(enterLeft, enterRight, enterCur, func(n *Node[T]) bool {
 		res = n.val
@@ -277,48 +305,73 @@ func (t *Treap[T]) Find(data T) (res T, exists bool) {
 
 // Range iterate over all elements in the treap
 func (t *Treap[T]) Range(f datastructure.ConditionFunc[T]) {
-	t.root.inorder(trueNodeConditionFunc[T], trueNodeConditionFunc[T], trueNodeConditionFunc[T], nodeConditionWrap[T](f))
+	t.iterate(bst.Unbounded[T](), bst.Unbounded[T](), nil, false, nodeConditionWrap(f))
 }
 
 // RangeS iterate over all elements E in the treap that satisfy E >= start
 func (t *Treap[T]) RangeS(start T, f datastructure.ConditionFunc[T]) {
-	enterLeft := func(root *Node[T]) bool {
-		return t.cmp.Compare(root.getValue(), start).GT()
-	}
-	enterCur := func(root *Node[T]) bool {
-		return t.cmp.Compare(root.getValue(), start).GTE()
-	}
-	t.root.inorder(enterLeft, enterCur, trueNodeConditionFunc[T], nodeConditionWrap[T](f))
+	t.iterate(bst.Included(start), bst.Unbounded[T](), nil, false, nodeConditionWrap(f))
 }
 
 // RangeSE iterate over all elements E in the treap that satisfy start <= E < end
 func (t *Treap[T]) RangeSE(start, end T, f datastructure.ConditionFunc[T]) {
-	enterLeft := func(root *Node[T]) bool {
-		return t.cmp.Compare(root.getValue(), start).GT()
-	}
-	enterCur := func(root *Node[T]) bool {
-		r1 := t.cmp.Compare(root.getValue(), start)
-		r2 := t.cmp.Compare(root.getValue(), end)
-		return r1.GTE() && r2.LT()
-	}
-	enterRight := func(root *Node[T]) bool {
-		return t.cmp.Compare(root.getValue(), end).LT()
-	}
-	t.root.inorder(enterLeft, enterCur, enterRight, nodeConditionWrap[T](f))
+	t.iterate(bst.Included(start), bst.Excluded(end), nil, false, nodeConditionWrap(f))
 }
 
 // RangeE iterate over all elements E in the treap that satisfy E < end
 func (t *Treap[T]) RangeE(end T, f datastructure.ConditionFunc[T]) {
-	enter := func(root *Node[T]) bool {
-		return t.cmp.Compare(root.getValue(), end).LT()
+	t.iterate(bst.Unbounded[T](), bst.Excluded(end), nil, false, nodeConditionWrap(f))
+}
+
+// RangeBounds iterate over all elements E in the Treap that are in the range from lo to hi in ascending order.
+// The iteration will be interrupted if f returns false.
+func (t *Treap[T]) RangeBounds(lo, hi bst.Bound[T], f datastructure.ConditionFunc[T]) {
+	t.iterate(lo, hi, nil, false, nodeConditionWrap(f))
+}
+
+// All return an iterator over all elements in the Treap in ascending order.
+func (t *Treap[T]) All() datastructure.Seq[T] {
+	return func(yield func(T) bool) {
+		t.Range(yield)
 	}
-	t.root.inorder(trueNodeConditionFunc[T], enter, enter, nodeConditionWrap[T](f))
+}
+
+// Backward return an iterator over all elements in the Treap in descending order.
+func (t *Treap[T]) Backward() datastructure.Seq[T] {
+	return func(yield func(T) bool) {
+		t.iterate(bst.Unbounded[T](), bst.Unbounded[T](), nil, true, nodeConditionWrap(yield))
+	}
+}
+
+// Between return an iterator over all elements E in the Treap that satisfy from <= E < to in ascending order.
+func (t *Treap[T]) Between(from, to T) datastructure.Seq[T] {
+	return func(yield func(T) bool) {
+		t.RangeSE(from, to, yield)
+	}
+}
+
+// Comparator return the compare.ICompare that orders the elements of the Treap.
+func (t *Treap[T]) Comparator() compare.ICompare[T] {
+	return t.cmp
 }
 
 // Private method
 
-func (t *Treap[T]) newNode(data T) *Node[T] {
+// compare return the result of the comparator, it panics if the result is not one of EQ, LT and GT.
+func (t *Treap[T]) compare(a, b T) compare.Result {
+	r := t.cmp.Compare(a, b)
+	if !r.Valid() {
+		panic(compare.InvalidResult(a, b, r))
+	}
+	return r
+}
+
+func (t *Treap[T]) newNode(data T, count int) *Node[T] {
 	node := t.alloc.Allocate()
+	node.count = count
+	node.removed = false
+	t.modCount++
+	t.created = node
 	node.priority = t.r()
 	node.setVal(data, t.countableCheck)
 	node.l = nil
@@ -327,9 +380,9 @@ func (t *Treap[T]) newNode(data T) *Node[T] {
 	return node
 }
 
-func (t *Treap[T]) insert(root *Node[T], data T, f nodeVisitFunc[T]) *Node[T] {
+func (t *Treap[T]) insert(root *Node[T], data T, count int, f nodeVisitFunc[T]) *Node[T] {
 	if root == nil {
-		return t.newNode(data)
+		return t.newNode(data, count)
 	}
 	result := t.cmp.Compare(root.val, data)
 	switch result {
@@ -338,17 +391,17 @@ func (t *Treap[T]) insert(root *Node[T], data T, f nodeVisitFunc[T]) *Node[T] {
 			f(root)
 		}
 	case compare.GT:
-		root.l = t.insert(root.l, data, f)
+		root.l = t.insert(root.l, data, count, f)
 		if root.l.priority < root.priority {
 			root = root.rightRotate()
 		}
 	case compare.LT:
-		root.r = t.insert(root.r, data, f)
+		root.r = t.insert(root.r, data, count, f)
 		if root.r.priority < root.priority {
 			root = root.leftRotate()
 		}
 	default:
-		panic("impossible")
+		panic(compare.InvalidResult(root.val, data, result))
 	}
 	root.pushUp()
 	return root
@@ -370,6 +423,10 @@ func (t *Treap[T]) delete(root *Node[T], data T, f nodeConditionFunc[T]) *Node[T
 		}
 		// make sure f is called only once
 		f = trueNodeConditionFunc[T]
+		if root.l == nil || root.r == nil {
+			root.removed = true
+			t.modCount++
+		}
 		if root.l == nil && root.r == nil {
 			root = nil
 			break
@@ -390,7 +447,44 @@ func (t *Treap[T]) delete(root *Node[T], data T, f nodeConditionFunc[T]) *Node[T
 			break
 		}
 	default:
-		panic("impossible")
+		panic(compare.InvalidResult(root.val, data, r))
+	}
+	root.pushUp()
+	return root
+}
+
+func (t *Treap[T]) compute(root *Node[T], data T, f datastructure.ComputeFunc[T], res *T, exists *bool) *Node[T] {
+	if root == nil {
+		var zero T
+		if v, keep := f(zero, false); keep {
+			*res, *exists = v, true
+			return t.newNode(v, 1)
+		}
+		return nil
+	}
+	r := t.cmp.Compare(root.val, data)
+	switch r {
+	case compare.EQ:
+		if v, keep := f(root.getValue(), true); keep {
+			root.setVal(v, t.countableCheck)
+			*res, *exists = v, true
+		} else if !t.removeOccurrence(root, 1) {
+			*res, *exists = root.getValue(), true
+		} else {
+			return t.delete(root, data, nil)
+		}
+	case compare.GT:
+		root.l = t.compute(root.l, data, f, res, exists)
+		if root.l != nil && root.l.priority < root.priority {
+			root = root.rightRotate()
+		}
+	case compare.LT:
+		root.r = t.compute(root.r, data, f, res, exists)
+		if root.r != nil && root.r.priority < root.priority {
+			root = root.leftRotate()
+		}
+	default:
+		panic(compare.InvalidResult(root.val, data, r))
 	}
 	root.pushUp()
 	return root
@@ -424,6 +518,6 @@ func (t *Treap[T]) rank(root *Node[T], data T) int {
 	case compare.GT:
 		return t.rank(root.l, data)
 	default:
-		panic("impossible")
+		panic(compare.InvalidResult(root.val, data, result))
 	}
 }
@@ -0,0 +1,85 @@
+package compare
+
+import "fmt"
+
+func (r Result) String() string {
+	switch r {
+	case EQ:
+		return "EQ"
+	case LT:
+		return "LT"
+	case GT:
+		return "GT"
+	default:
+		return fmt.Sprintf("Result(%d)", int(r))
+	}
+}
+
+// Valid return true if r is one of EQ, LT and GT.
+func (r Result) Valid() bool {
+	return r == EQ || r == LT || r == GT
+}
+
+// InconsistentError reports a comparator that violates the contract of ICompare.
+type InconsistentError struct {
+	// A and B are the offending values, B is nil if the violation involves a single value.
+	A, B any
+	// Result is the result of Compare(A, B).
+	Result Result
+	// Reverse is the result of Compare(B, A), it is zero if it was not evaluated.
+	Reverse Result
+	// Reason describes the violated rule.
+	Reason string
+}
+
+func (e *InconsistentError) Error() string {
+	if e.B == nil {
+		return fmt.Sprintf("compare: inconsistent comparator: %v: %v for %v", e.Reason, e.Result, e.A)
+	}
+	if e.Reverse == 0 {
+		return fmt.Sprintf("compare: inconsistent comparator: %v: Compare(%v, %v) = %v",
+			e.Reason, e.A, e.B, e.Result)
+	}
+	return fmt.Sprintf("compare: inconsistent comparator: %v: Compare(%v, %v) = %v, Compare(%v, %v) = %v",
+		e.Reason, e.A, e.B, e.Result, e.B, e.A, e.Reverse)
+}
+
+// InvalidResult return the error for a comparator that returns r, which is not one of EQ, LT and GT.
+func InvalidResult(a, b any, r Result) *InconsistentError {
+	return &InconsistentError{A: a, B: b, Result: r, Reason: "invalid result"}
+}
+
+// Checked wraps cmp and checks the contract of ICompare on every comparison:
+// the result must be one of EQ, LT and GT, every value must be EQ to itself,
+// and Compare(b, a) must be the opposite of Compare(a, b).
+// It panics with *InconsistentError on violation.
+// Every comparison calls cmp four times, so it is intended for debugging and testing.
+func Checked[T any](cmp ICompare[T]) ICompare[T] {
+	return CheckedWithReport(cmp, func(err *InconsistentError) {
+		panic(err)
+	})
+}
+
+// CheckedWithReport is like Checked, but the violations are passed to report instead of panicking,
+// and the result of cmp is returned as is.
+func CheckedWithReport[T any](cmp ICompare[T], report func(err *InconsistentError)) ICompare[T] {
+	return WithFunc[T](func(a, b T) Result {
+		r := cmp.Compare(a, b)
+		if !r.Valid() {
+			report(InvalidResult(a, b, r))
+			return r
+		}
+		if ra := cmp.Compare(a, a); ra != EQ {
+			report(&InconsistentError{A: a, B: a, Result: ra, Reason: "not reflexive"})
+			return r
+		}
+		if rb := cmp.Compare(b, b); rb != EQ {
+			report(&InconsistentError{A: b, B: b, Result: rb, Reason: "not reflexive"})
+			return r
+		}
+		if reverse := cmp.Compare(b, a); reverse != r.Reverse() {
+			report(&InconsistentError{A: a, B: b, Result: r, Reverse: reverse, Reason: "not antisymmetric"})
+		}
+		return r
+	})
+}
@@ -0,0 +1,40 @@
+package compare
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecked(t *testing.T) {
+	good := Checked(OrderedLessCompareF[int]())
+	assert.Equal(t, LT, good.Compare(1, 2))
+	assert.Equal(t, EQ, good.Compare(2, 2))
+
+	zero := Checked[int](WithFunc[int](func(a, b int) Result { return 0 }))
+	assert.PanicsWithError(t, "compare: inconsistent comparator: invalid result: Compare(1, 2) = Result(0)", func() {
+		zero.Compare(1, 2)
+	})
+
+	lessOrEqual := Checked(LessF[int](func(a, b int) bool { return a <= b }))
+	assert.PanicsWithError(t, "compare: inconsistent comparator: not reflexive: Compare(1, 1) = LT", func() {
+		lessOrEqual.Compare(1, 2)
+	})
+
+	alwaysLT := WithFunc[int](func(a, b int) Result {
+		if a == b {
+			return EQ
+		}
+		return LT
+	})
+	var reported []*InconsistentError
+	r := CheckedWithReport(alwaysLT, func(err *InconsistentError) {
+		reported = append(reported, err)
+	}).Compare(1, 2)
+	assert.Equal(t, LT, r)
+	if assert.Len(t, reported, 1) {
+		assert.Equal(t, "not antisymmetric", reported[0].Reason)
+		assert.Equal(t, LT, reported[0].Reverse)
+		assert.Equal(t, "compare: inconsistent comparator: not antisymmetric: Compare(1, 2) = LT, Compare(2, 1) = LT", reported[0].Error())
+	}
+}
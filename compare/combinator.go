@@ -0,0 +1,112 @@
+package compare
+
+// Int return the Result as an int, -1 for LT, 0 for EQ and +1 for GT,
+// which is the convention of cmp.Compare and slices.SortFunc.
+func (r Result) Int() int {
+	switch r {
+	case LT:
+		return -1
+	case EQ:
+		return 0
+	case GT:
+		return 1
+	default:
+		panic("compare: invalid Result")
+	}
+}
+
+// Reverse return the opposite Result, LT becomes GT and GT becomes LT.
+func (r Result) Reverse() Result {
+	switch r {
+	case LT:
+		return GT
+	case GT:
+		return LT
+	default:
+		return r
+	}
+}
+
+// FromInt convert an int returned by a cmp.Compare style function to Result.
+// Any negative number is LT, zero is EQ and any positive number is GT.
+func FromInt(i int) Result {
+	if i < 0 {
+		return LT
+	} else if i == 0 {
+		return EQ
+	} else {
+		return GT
+	}
+}
+
+// FromCmp wraps a cmp.Compare style function as ICompare.
+func FromCmp[T any](f func(a, b T) int) ICompare[T] {
+	return WithFunc[T](func(a, b T) Result {
+		return FromInt(f(a, b))
+	})
+}
+
+// ToCmp return a cmp.Compare style function of cmp,
+// so that cmp can be used with slices.SortFunc, slices.BinarySearchFunc etc.
+func ToCmp[T any](cmp ICompare[T]) func(a, b T) int {
+	return func(a, b T) int {
+		return cmp.Compare(a, b).Int()
+	}
+}
+
+// Reverse return an ICompare that orders the elements in the opposite order of cmp.
+func Reverse[T any](cmp ICompare[T]) ICompare[T] {
+	return WithFunc[T](func(a, b T) Result {
+		return cmp.Compare(b, a)
+	})
+}
+
+// ThenBy return an ICompare that compares by cmp first,
+// the elements that cmp considers EQ are compared by the next comparator in then.
+func ThenBy[T any](cmp ICompare[T], then ...ICompare[T]) ICompare[T] {
+	return WithFunc[T](func(a, b T) Result {
+		r := cmp.Compare(a, b)
+		for i := 0; r == EQ && i < len(then); i++ {
+			r = then[i].Compare(a, b)
+		}
+		return r
+	})
+}
+
+// By return an ICompare that compares the keys extracted by key with cmp.
+// It is useful to compare structs by a field.
+func By[T any, K any](key func(T) K, cmp ICompare[K]) ICompare[T] {
+	return WithFunc[T](func(a, b T) Result {
+		return cmp.Compare(key(a), key(b))
+	})
+}
+
+// NilsFirst return an ICompare for pointers, nil is less than any non-nil pointer,
+// and the non-nil pointers are compared by the value they point to.
+func NilsFirst[T any](cmp ICompare[T]) ICompare[*T] {
+	return WithFunc[*T](func(a, b *T) Result {
+		if a == nil && b == nil {
+			return EQ
+		} else if a == nil {
+			return LT
+		} else if b == nil {
+			return GT
+		}
+		return cmp.Compare(*a, *b)
+	})
+}
+
+// NilsLast return an ICompare for pointers, nil is greater than any non-nil pointer,
+// and the non-nil pointers are compared by the value they point to.
+func NilsLast[T any](cmp ICompare[T]) ICompare[*T] {
+	return WithFunc[*T](func(a, b *T) Result {
+		if a == nil && b == nil {
+			return EQ
+		} else if a == nil {
+			return GT
+		} else if b == nil {
+			return LT
+		}
+		return cmp.Compare(*a, *b)
+	})
+}
@@ -0,0 +1,64 @@
+package compare
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type person struct {
+	name string
+	age  int
+}
+
+func TestResultInt(t *testing.T) {
+	assert.Equal(t, -1, LT.Int())
+	assert.Equal(t, 0, EQ.Int())
+	assert.Equal(t, 1, GT.Int())
+	assert.Panics(t, func() { Result(0).Int() })
+	for _, r := range []Result{LT, EQ, GT} {
+		assert.Equal(t, r, FromInt(r.Int()))
+	}
+	assert.Equal(t, LT, FromInt(-100))
+	assert.Equal(t, GT, FromInt(100))
+	assert.Equal(t, GT, LT.Reverse())
+	assert.Equal(t, EQ, EQ.Reverse())
+}
+
+func TestCmpInterop(t *testing.T) {
+	c := FromCmp[string](strings.Compare)
+	assert.Equal(t, LT, c.Compare("a", "b"))
+	assert.Equal(t, EQ, c.Compare("a", "a"))
+	f := ToCmp[int](OrderedLessCompareF[int]())
+	assert.Equal(t, -1, f(1, 2))
+	assert.Equal(t, 1, f(2, 1))
+	assert.Equal(t, 0, f(2, 2))
+}
+
+func TestCombinator(t *testing.T) {
+	people := []person{{"bob", 30}, {"alice", 30}, {"carol", 25}, {"alice", 20}}
+	byAge := By(func(p person) int { return p.age }, OrderedLessCompareF[int]())
+	byName := By(func(p person) string { return p.name }, OrderedLessCompareF[string]())
+	cmp := ThenBy(Reverse(byAge), byName)
+	sort.Slice(people, func(i, j int) bool {
+		return cmp.Compare(people[i], people[j]).LT()
+	})
+	assert.Equal(t, []person{{"alice", 30}, {"bob", 30}, {"carol", 25}, {"alice", 20}}, people)
+	assert.Equal(t, EQ, ThenBy(byAge).Compare(person{"x", 1}, person{"y", 1}))
+}
+
+func TestNils(t *testing.T) {
+	one, two := 1, 2
+	first := NilsFirst(OrderedLessCompareF[int]())
+	last := NilsLast(OrderedLessCompareF[int]())
+	assert.Equal(t, EQ, first.Compare(nil, nil))
+	assert.Equal(t, LT, first.Compare(nil, &one))
+	assert.Equal(t, GT, first.Compare(&one, nil))
+	assert.Equal(t, LT, first.Compare(&one, &two))
+	assert.Equal(t, EQ, last.Compare(nil, nil))
+	assert.Equal(t, GT, last.Compare(nil, &one))
+	assert.Equal(t, LT, last.Compare(&one, nil))
+	assert.Equal(t, GT, last.Compare(&two, &one))
+}
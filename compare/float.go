@@ -0,0 +1,52 @@
+package compare
+
+type Float interface {
+	~float32 | ~float64
+}
+
+// FloatNaNFirstCompare compares floats in ascending order with a total order:
+// NaN is less than any other value and all NaNs are EQ to each other,
+// -0 and +0 are EQ, just like they are for the == operator.
+func FloatNaNFirstCompare[T Float](a, b T) Result {
+	if a < b {
+		return LT
+	} else if a == b {
+		return EQ
+	} else if a > b {
+		return GT
+	}
+	return nanFirstCompare(a != a, b != b)
+}
+
+// FloatNaNLastCompare compares floats in ascending order with a total order:
+// NaN is greater than any other value and all NaNs are EQ to each other,
+// -0 and +0 are EQ, just like they are for the == operator.
+func FloatNaNLastCompare[T Float](a, b T) Result {
+	if a < b {
+		return LT
+	} else if a == b {
+		return EQ
+	} else if a > b {
+		return GT
+	}
+	return nanFirstCompare(a != a, b != b).Reverse()
+}
+
+func FloatNaNFirstCompareF[T Float]() ICompare[T] {
+	return WithFunc[T](FloatNaNFirstCompare[T])
+}
+
+func FloatNaNLastCompareF[T Float]() ICompare[T] {
+	return WithFunc[T](FloatNaNLastCompare[T])
+}
+
+// nanFirstCompare compares two unordered values, at least one of them is NaN.
+func nanFirstCompare(aNaN, bNaN bool) Result {
+	if aNaN && bNaN {
+		return EQ
+	} else if aNaN {
+		return LT
+	} else {
+		return GT
+	}
+}
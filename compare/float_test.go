@@ -0,0 +1,33 @@
+package compare
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFloatCompare(t *testing.T) {
+	nan := math.NaN()
+	negZero := math.Copysign(0, -1)
+	for _, f := range []func(a, b float64) Result{FloatNaNFirstCompare[float64], OrderedLessCompare[float64]} {
+		assert.Equal(t, EQ, f(nan, nan))
+		assert.Equal(t, LT, f(nan, math.Inf(-1)))
+		assert.Equal(t, GT, f(1, nan))
+		assert.Equal(t, EQ, f(negZero, 0))
+		assert.Equal(t, LT, f(-1, 1))
+	}
+	assert.Equal(t, EQ, FloatNaNLastCompare(nan, nan))
+	assert.Equal(t, GT, FloatNaNLastCompare(nan, math.Inf(1)))
+	assert.Equal(t, LT, FloatNaNLastCompare(1, nan))
+	assert.Equal(t, LT, OrderedGreaterCompare(1, nan))
+	assert.Equal(t, GT, OrderedGreaterCompare(1, 2))
+
+	data := []float32{3, float32(nan), -1, float32(math.Inf(1)), float32(nan), 0}
+	sort.Slice(data, func(i, j int) bool {
+		return FloatNaNLastCompare(data[i], data[j]).LT()
+	})
+	assert.Equal(t, []float32{-1, 0, 3, float32(math.Inf(1))}, data[:4])
+	assert.True(t, data[4] != data[4] && data[5] != data[5])
+}
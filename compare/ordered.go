@@ -2,29 +2,29 @@ package compare
 
 type Ordered interface {
 	~float32 | ~float64 |
-	~int | ~int8 | ~int16 | ~int32 | ~int64 |
-	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
-	~string
+		~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~string
 }
 
+// OrderedLessCompare compares a and b in ascending order.
+// For float types it imposes a total order, see FloatNaNFirstCompare.
 func OrderedLessCompare[T Ordered](a, b T) Result {
 	if a < b {
 		return LT
 	} else if a == b {
 		return EQ
-	} else {
+	} else if a > b {
 		return GT
 	}
+	// a and b are unordered, at least one of them is NaN
+	return nanFirstCompare(a != a, b != b)
 }
 
+// OrderedGreaterCompare compares a and b in descending order.
+// For float types NaN is ordered after any other value.
 func OrderedGreaterCompare[T Ordered](a, b T) Result {
-	if a > b {
-		return LT
-	} else if a == b {
-		return EQ
-	} else {
-		return GT
-	}
+	return OrderedLessCompare(b, a)
 }
 
 func OrderedLessCompareF[T Ordered]() ICompare[T] {
@@ -0,0 +1,114 @@
+package compare
+
+import (
+	"bytes"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NaturalLessCompare compares strings in natural order,
+// the runs of decimal digits are compared by their numeric value, so "file2" < "file10".
+// The strings that only differ in leading zeros, such as "a01" and "a1", are ordered bytewise.
+func NaturalLessCompare[T ~string](a, b T) Result {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if isDigit(a[i]) && isDigit(b[j]) {
+			// skip the leading zeros
+			for i < len(a) && a[i] == '0' {
+				i++
+			}
+			for j < len(b) && b[j] == '0' {
+				j++
+			}
+			si, sj := i, j
+			for i < len(a) && isDigit(a[i]) {
+				i++
+			}
+			for j < len(b) && isDigit(b[j]) {
+				j++
+			}
+			// the number that has more significant digits is greater
+			if i-si != j-sj {
+				return OrderedLessCompare(i-si, j-sj)
+			}
+			if r := OrderedLessCompare(a[si:i], b[sj:j]); r != EQ {
+				return r
+			}
+			continue
+		}
+		if a[i] != b[j] {
+			return OrderedLessCompare(a[i], b[j])
+		}
+		i++
+		j++
+	}
+	if r := OrderedLessCompare(len(a)-i, len(b)-j); r != EQ {
+		return r
+	}
+	return OrderedLessCompare(a, b)
+}
+
+func NaturalLessCompareF[T ~string]() ICompare[T] {
+	return WithFunc[T](NaturalLessCompare[T])
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// FoldLessCompare compares strings under Unicode simple case folding,
+// the strings that strings.EqualFold reports equal are EQ, such as "Go" and "GO".
+// Use ThenBy(FoldLessCompareF[T](), OrderedLessCompareF[T]()) to keep such strings apart.
+func FoldLessCompare[T ~string](a, b T) Result {
+	for len(a) > 0 && len(b) > 0 {
+		var ra, rb rune
+		var sa, sb int
+		if a[0] < utf8.RuneSelf {
+			ra, sa = rune(a[0]), 1
+		} else {
+			ra, sa = utf8.DecodeRuneInString(string(a))
+		}
+		if b[0] < utf8.RuneSelf {
+			rb, sb = rune(b[0]), 1
+		} else {
+			rb, sb = utf8.DecodeRuneInString(string(b))
+		}
+		if ra != rb {
+			if r := OrderedLessCompare(foldRune(ra), foldRune(rb)); r != EQ {
+				return r
+			}
+		}
+		a, b = a[sa:], b[sb:]
+	}
+	return OrderedLessCompare(len(a), len(b))
+}
+
+func FoldLessCompareF[T ~string]() ICompare[T] {
+	return WithFunc[T](FoldLessCompare[T])
+}
+
+// foldRune return the smallest rune that is equivalent to r under simple case folding.
+func foldRune(r rune) rune {
+	if r < utf8.RuneSelf {
+		if 'a' <= r && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		return r
+	}
+	res := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < res {
+			res = f
+		}
+	}
+	return res
+}
+
+// BytesLessCompare compares byte slices lexicographically, a nil slice is EQ to an empty slice.
+func BytesLessCompare[T ~[]byte](a, b T) Result {
+	return FromInt(bytes.Compare(a, b))
+}
+
+func BytesLessCompareF[T ~[]byte]() ICompare[T] {
+	return WithFunc[T](BytesLessCompare[T])
+}
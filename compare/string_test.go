@@ -0,0 +1,42 @@
+package compare
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNaturalLessCompare(t *testing.T) {
+	data := []string{"file10", "file2", "file1", "file02", "file", "file10a", "File3", "x", "10", "9", "a0b", "a00b"}
+	sort.Slice(data, func(i, j int) bool {
+		return NaturalLessCompare(data[i], data[j]).LT()
+	})
+	assert.Equal(t, []string{"9", "10", "File3", "a00b", "a0b", "file", "file1", "file02", "file2", "file10", "file10a", "x"}, data)
+	assert.Equal(t, EQ, NaturalLessCompare("file10", "file10"))
+	assert.Equal(t, LT, NaturalLessCompare("file02", "file2"))
+	assert.Equal(t, GT, NaturalLessCompare("file2", "file02"))
+	assert.Equal(t, LT, NaturalLessCompare("", "0"))
+}
+
+func TestFoldLessCompare(t *testing.T) {
+	assert.Equal(t, EQ, FoldLessCompare("Go", "gO"))
+	assert.NotEqual(t, EQ, FoldLessCompare("straße", "STRASSE"))
+	assert.Equal(t, EQ, FoldLessCompare("k", "K"))
+	assert.Equal(t, EQ, FoldLessCompare("Σ", "ς"))
+	assert.Equal(t, LT, FoldLessCompare("apple", "Banana"))
+	assert.Equal(t, GT, FoldLessCompare("apples", "APPLE"))
+	strict := ThenBy(FoldLessCompareF[string](), OrderedLessCompareF[string]())
+	data := []string{"b", "B", "a", "A"}
+	sort.Slice(data, func(i, j int) bool {
+		return strict.Compare(data[i], data[j]).LT()
+	})
+	assert.Equal(t, []string{"A", "a", "B", "b"}, data)
+}
+
+func TestBytesLessCompare(t *testing.T) {
+	assert.Equal(t, EQ, BytesLessCompare([]byte(nil), []byte{}))
+	assert.Equal(t, LT, BytesLessCompare([]byte("ab"), []byte("b")))
+	assert.Equal(t, GT, BytesLessCompare([]byte("ab"), []byte("a")))
+	assert.Equal(t, LT, BytesLessCompareF[[]byte]().Compare([]byte{0}, []byte{0, 0}))
+}
@@ -5,6 +5,10 @@ import (
 	"github.com/Sora233/datastructure/compare"
 )
 
+// Duplicate counts the occurrences of Key for a tree of bst.Countable elements.
+// The count is shared by every copy of a Duplicate, so it must only be changed through
+// the tree's InsertOrVisit and DeleteIf, or replaced by Update, otherwise the sizes of the tree go stale.
+// The multiset mode of the trees, such as avl.WithMultiset, keeps the count in the tree itself instead.
 type Duplicate[T any] struct {
 	Key   T
 	count *int
@@ -21,7 +25,12 @@ func (du Duplicate[T]) Add(delta uint) {
 	*du.count += int(delta)
 }
 
+// Sub decreases the count by delta, the count never goes below zero.
 func (du Duplicate[T]) Sub(delta uint) {
+	if *du.count < int(delta) {
+		*du.count = 0
+		return
+	}
 	*du.count -= int(delta)
 }
 
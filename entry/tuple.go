@@ -0,0 +1,121 @@
+package entry
+
+import "github.com/Sora233/datastructure/compare"
+
+// Pair is a composite key of two components.
+// Pairs are ordered lexicographically by PairCompareWrapper: by First, then by Second.
+type Pair[A any, B any] struct {
+	First  A
+	Second B
+	// bound is non-zero for the probes created by PairPrefix,
+	// a negative bound sorts before and a positive bound sorts after
+	// every Pair that has the same First.
+	bound int8
+}
+
+func NewPair[A any, B any](first A, second B) Pair[A, B] {
+	return Pair[A, B]{First: first, Second: second}
+}
+
+// PairPrefix return the probes that enclose all the pairs whose First is EQ to first,
+// use them as [start, end) of bst.BinarySearchTree.RangeSE.
+// The probes are only meaningful to the comparators created by PairCompareWrapper,
+// and should not be stored in a tree.
+func PairPrefix[A any, B any](first A) (start, end Pair[A, B]) {
+	start = Pair[A, B]{First: first, bound: -1}
+	end = Pair[A, B]{First: first, bound: 1}
+	return
+}
+
+// PairCompareWrapper compose the comparators of each component into a lexicographic comparator of Pair.
+func PairCompareWrapper[A any, B any](first compare.ICompare[A], second compare.ICompare[B]) compare.ICompare[Pair[A, B]] {
+	return compare.WithFunc[Pair[A, B]](func(a, b Pair[A, B]) compare.Result {
+		if r := first.Compare(a.First, b.First); r != compare.EQ {
+			return r
+		}
+		if r, ok := probeCompare(a.bound, b.bound); ok {
+			return r
+		}
+		return second.Compare(a.Second, b.Second)
+	})
+}
+
+func OrderedPairLessCompareF[A compare.Ordered, B compare.Ordered]() compare.ICompare[Pair[A, B]] {
+	return PairCompareWrapper[A, B](compare.OrderedLessCompareF[A](), compare.OrderedLessCompareF[B]())
+}
+
+// Triple is a composite key of three components.
+// Triples are ordered lexicographically by TripleCompareWrapper: by First, then by Second, then by Third.
+type Triple[A any, B any, C any] struct {
+	First  A
+	Second B
+	Third  C
+	// bound is non-zero for the probes created by TriplePrefix and TriplePrefix2,
+	// depth is the number of components that the probe has.
+	bound int8
+	depth int8
+}
+
+func NewTriple[A any, B any, C any](first A, second B, third C) Triple[A, B, C] {
+	return Triple[A, B, C]{First: first, Second: second, Third: third}
+}
+
+// TriplePrefix return the probes that enclose all the triples whose First is EQ to first,
+// use them as [start, end) of bst.BinarySearchTree.RangeSE.
+// The probes are only meaningful to the comparators created by TripleCompareWrapper,
+// and should not be stored in a tree.
+func TriplePrefix[A any, B any, C any](first A) (start, end Triple[A, B, C]) {
+	start = Triple[A, B, C]{First: first, bound: -1, depth: 1}
+	end = Triple[A, B, C]{First: first, bound: 1, depth: 1}
+	return
+}
+
+// TriplePrefix2 return the probes that enclose all the triples whose First and Second are EQ to first and second,
+// use them as [start, end) of bst.BinarySearchTree.RangeSE.
+// The probes are only meaningful to the comparators created by TripleCompareWrapper,
+// and should not be stored in a tree.
+func TriplePrefix2[A any, B any, C any](first A, second B) (start, end Triple[A, B, C]) {
+	start = Triple[A, B, C]{First: first, Second: second, bound: -1, depth: 2}
+	end = Triple[A, B, C]{First: first, Second: second, bound: 1, depth: 2}
+	return
+}
+
+// TripleCompareWrapper compose the comparators of each component into a lexicographic comparator of Triple.
+func TripleCompareWrapper[A any, B any, C any](first compare.ICompare[A], second compare.ICompare[B], third compare.ICompare[C]) compare.ICompare[Triple[A, B, C]] {
+	return compare.WithFunc[Triple[A, B, C]](func(a, b Triple[A, B, C]) compare.Result {
+		if r := first.Compare(a.First, b.First); r != compare.EQ {
+			return r
+		}
+		if r, ok := probeCompare(a.boundAt(1), b.boundAt(1)); ok {
+			return r
+		}
+		if r := second.Compare(a.Second, b.Second); r != compare.EQ {
+			return r
+		}
+		if r, ok := probeCompare(a.boundAt(2), b.boundAt(2)); ok {
+			return r
+		}
+		return third.Compare(a.Third, b.Third)
+	})
+}
+
+func OrderedTripleLessCompareF[A compare.Ordered, B compare.Ordered, C compare.Ordered]() compare.ICompare[Triple[A, B, C]] {
+	return TripleCompareWrapper[A, B, C](compare.OrderedLessCompareF[A](), compare.OrderedLessCompareF[B](), compare.OrderedLessCompareF[C]())
+}
+
+// boundAt return the bound of the probe if it ends after depth components, or 0.
+func (t Triple[A, B, C]) boundAt(depth int8) int8 {
+	if t.depth == depth {
+		return t.bound
+	}
+	return 0
+}
+
+// probeCompare compares a and b whose leading components are EQ.
+// It returns false if neither of them is a probe that ends here.
+func probeCompare(a, b int8) (compare.Result, bool) {
+	if a == 0 && b == 0 {
+		return 0, false
+	}
+	return compare.OrderedLessCompare(a, b), true
+}
@@ -0,0 +1,61 @@
+package entry_test
+
+import (
+	"testing"
+
+	"github.com/Sora233/datastructure/bst/avl"
+	"github.com/Sora233/datastructure/compare"
+	"github.com/Sora233/datastructure/entry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPair(t *testing.T) {
+	cmp := entry.OrderedPairLessCompareF[int, string]()
+	assert.Equal(t, compare.LT, cmp.Compare(entry.NewPair(1, "b"), entry.NewPair(2, "a")))
+	assert.Equal(t, compare.LT, cmp.Compare(entry.NewPair(1, "a"), entry.NewPair(1, "b")))
+	assert.Equal(t, compare.EQ, cmp.Compare(entry.NewPair(1, "a"), entry.NewPair(1, "a")))
+
+	tree := avl.New(cmp)
+	tree.Insert(entry.NewPair(1, "x"))
+	tree.Insert(entry.NewPair(2, ""))
+	tree.Insert(entry.NewPair(2, "b"))
+	tree.Insert(entry.NewPair(2, "a"))
+	tree.Insert(entry.NewPair(3, "a"))
+	start, end := entry.PairPrefix[int, string](2)
+	var actual []string
+	tree.RangeSE(start, end, func(p entry.Pair[int, string]) bool {
+		actual = append(actual, p.Second)
+		return true
+	})
+	assert.Equal(t, []string{"", "a", "b"}, actual)
+	assert.Equal(t, 2, tree.Rank(start))
+	assert.Equal(t, 5, tree.Rank(end))
+}
+
+func TestTriple(t *testing.T) {
+	cmp := entry.OrderedTripleLessCompareF[string, int, int]()
+	tree := avl.New(cmp)
+	for _, tenant := range []string{"a", "b", "c"} {
+		for ts := 0; ts < 3; ts++ {
+			for id := 0; id < 3; id++ {
+				tree.Insert(entry.NewTriple(tenant, ts, id))
+			}
+		}
+	}
+	count := func(start, end entry.Triple[string, int, int]) (n int) {
+		tree.RangeSE(start, end, func(entry.Triple[string, int, int]) bool {
+			n++
+			return true
+		})
+		return
+	}
+	assert.Equal(t, 9, count(entry.TriplePrefix[string, int, int]("b")))
+	assert.Equal(t, 3, count(entry.TriplePrefix2[string, int, int]("b", 1)))
+	assert.Equal(t, 0, count(entry.TriplePrefix2[string, int, int]("b", 5)))
+	start, _ := entry.TriplePrefix2[string, int, int]("b", 1)
+	_, end := entry.TriplePrefix[string, int, int]("b")
+	assert.Equal(t, 6, count(start, end))
+	first, ok := tree.FindOrNext(start)
+	assert.True(t, ok)
+	assert.Equal(t, entry.NewTriple("b", 1, 0), first)
+}
@@ -44,5 +44,5 @@ type Heap[T any] interface {
 	// DecreaseKey modify the oldKey by function change.
 	// This operator panics when new key is greater than oldKey
 	// return true if the key exists and modify success.
-	DecreaseKey(oldKey, change datastructure.ModifyFunc[T]) (success bool)
+	DecreaseKey(oldKey T, change datastructure.ModifyFunc[T]) (success bool)
 }
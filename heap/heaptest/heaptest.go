@@ -0,0 +1,211 @@
+// Package heaptest provides a conformance test suite for implementations of heap.Heap.
+package heaptest
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/Sora233/datastructure/compare"
+	"github.com/Sora233/datastructure/heap"
+)
+
+// Factory create an empty heap that orders the elements by cmp.
+type Factory[T any] func(cmp compare.ICompare[T]) heap.Heap[T]
+
+// Run runs the conformance test suite against the heaps created by factory.
+func Run(t *testing.T, factory Factory[int]) {
+	newHeap := func() heap.Heap[int] {
+		h := factory(compare.OrderedLessCompareF[int]())
+		if h == nil {
+			t.Fatal("factory returned a nil heap")
+		}
+		return h
+	}
+	t.Run("Empty", func(t *testing.T) { testEmpty(t, newHeap()) })
+	t.Run("Insert", func(t *testing.T) { testInsert(t, newHeap()) })
+	t.Run("Pop", func(t *testing.T) { testPop(t, newHeap()) })
+	t.Run("PopIf", func(t *testing.T) { testPopIf(t, newHeap()) })
+	t.Run("DecreaseKey", func(t *testing.T) { testDecreaseKey(t, newHeap()) })
+	t.Run("Clear", func(t *testing.T) { testClear(t, newHeap()) })
+	t.Run("Random", func(t *testing.T) { testRandom(t, newHeap()) })
+}
+
+// model is the reference implementation that the heap is checked against.
+type model map[int]struct{}
+
+func (m model) min() (res int, exists bool) {
+	for k := range m {
+		if !exists || k < res {
+			res = k
+			exists = true
+		}
+	}
+	return
+}
+
+func (m model) sorted() []int {
+	var res []int
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Ints(res)
+	return res
+}
+
+func checkHeap(t *testing.T, h heap.Heap[int], m model) {
+	t.Helper()
+	if h.Size() != len(m) {
+		t.Fatalf("Size: expected %v, got %v", len(m), h.Size())
+	}
+	if h.Empty() != (len(m) == 0) {
+		t.Fatalf("Empty: expected %v, got %v", len(m) == 0, h.Empty())
+	}
+	expected, expectedOk := m.min()
+	actual, actualOk := h.Top()
+	if expectedOk != actualOk || expected != actual {
+		t.Fatalf("Top: expected %v, %v, got %v, %v", expected, expectedOk, actual, actualOk)
+	}
+}
+
+func testEmpty(t *testing.T, h heap.Heap[int]) {
+	checkHeap(t, h, model{})
+	if _, ok := h.Pop(); ok {
+		t.Fatal("Pop on empty heap: expected not exists")
+	}
+	if h.PopIf(func(int) bool { t.Fatal("PopIf on empty heap: f called"); return true }) {
+		t.Fatal("PopIf on empty heap: expected no effect")
+	}
+	if h.DecreaseKey(1, func(old int) int { t.Fatal("DecreaseKey on empty heap: f called"); return old }) {
+		t.Fatal("DecreaseKey on empty heap: expected no effect")
+	}
+}
+
+func testInsert(t *testing.T, h heap.Heap[int]) {
+	m := model{}
+	for _, v := range []int{5, 3, 8, 1, 9} {
+		if _, replaced := h.Insert(v); replaced {
+			t.Fatalf("Insert(%v): expected not replaced", v)
+		}
+		m[v] = struct{}{}
+		checkHeap(t, h, m)
+	}
+	if old, replaced := h.Insert(3); !replaced || old != 3 {
+		t.Fatalf("Insert(3) on existing element: expected replaced, got %v, %v", old, replaced)
+	}
+	if h.InsertOrIgnore(8) {
+		t.Fatal("InsertOrIgnore(8) on existing element: expected no effect")
+	}
+	if !h.InsertOrIgnore(0) {
+		t.Fatal("InsertOrIgnore(0): expected success")
+	}
+	m[0] = struct{}{}
+	var calls []int
+	h.InsertOrVisit(9, func(v int) { calls = append(calls, v) })
+	if len(calls) != 1 || calls[0] != 9 {
+		t.Fatalf("InsertOrVisit(9): expected f called once with 9, got %v", calls)
+	}
+	h.InsertOrVisit(-1, func(v int) { t.Fatal("InsertOrVisit(-1): f called on absent element") })
+	m[-1] = struct{}{}
+	checkHeap(t, h, m)
+}
+
+func testPop(t *testing.T, h heap.Heap[int]) {
+	m := model{}
+	for _, v := range []int{7, 2, 9, 4, 1, 8, 3} {
+		h.Insert(v)
+		m[v] = struct{}{}
+	}
+	for _, expected := range m.sorted() {
+		actual, ok := h.Pop()
+		if !ok || actual != expected {
+			t.Fatalf("Pop: expected %v, got %v, %v", expected, actual, ok)
+		}
+		delete(m, expected)
+		checkHeap(t, h, m)
+	}
+}
+
+func testPopIf(t *testing.T, h heap.Heap[int]) {
+	m := model{}
+	for _, v := range []int{4, 2, 6} {
+		h.Insert(v)
+		m[v] = struct{}{}
+	}
+	var calls []int
+	if h.PopIf(func(v int) bool { calls = append(calls, v); return false }) {
+		t.Fatal("PopIf: expected no effect when f returns false")
+	}
+	if len(calls) != 1 || calls[0] != 2 {
+		t.Fatalf("PopIf: expected f called once with 2, got %v", calls)
+	}
+	checkHeap(t, h, m)
+	calls = nil
+	if !h.PopIf(func(v int) bool { calls = append(calls, v); return true }) {
+		t.Fatal("PopIf: expected success when f returns true")
+	}
+	if len(calls) != 1 || calls[0] != 2 {
+		t.Fatalf("PopIf: expected f called once with 2, got %v", calls)
+	}
+	delete(m, 2)
+	checkHeap(t, h, m)
+}
+
+func testDecreaseKey(t *testing.T, h heap.Heap[int]) {
+	m := model{}
+	for _, v := range []int{10, 20, 30, 40} {
+		h.Insert(v)
+		m[v] = struct{}{}
+	}
+	if !h.DecreaseKey(30, func(old int) int { return old - 25 }) {
+		t.Fatal("DecreaseKey(30): expected success")
+	}
+	delete(m, 30)
+	m[5] = struct{}{}
+	checkHeap(t, h, m)
+	if h.DecreaseKey(30, func(old int) int { t.Fatal("DecreaseKey(30): f called on absent element"); return old }) {
+		t.Fatal("DecreaseKey(30): expected no effect on absent element")
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("DecreaseKey(40) with a bigger key: expected panic")
+			}
+		}()
+		h.DecreaseKey(40, func(old int) int { return old + 1 })
+	}()
+}
+
+func testClear(t *testing.T, h heap.Heap[int]) {
+	for v := 0; v < 100; v++ {
+		h.Insert(v)
+	}
+	h.Clear()
+	checkHeap(t, h, model{})
+	h.Insert(1)
+	checkHeap(t, h, model{1: {}})
+}
+
+func testRandom(t *testing.T, h heap.Heap[int]) {
+	r := rand.New(rand.NewSource(20231019))
+	m := model{}
+	for i := 0; i < 5000; i++ {
+		switch r.Intn(3) {
+		case 0, 1:
+			v := r.Intn(1000)
+			_, found := m[v]
+			if h.InsertOrIgnore(v) == found {
+				t.Fatalf("InsertOrIgnore(%v): expected success %v", v, !found)
+			}
+			m[v] = struct{}{}
+		case 2:
+			expected, expectedOk := m.min()
+			actual, actualOk := h.Pop()
+			if expectedOk != actualOk || expected != actual {
+				t.Fatalf("Pop: expected %v, %v, got %v, %v", expected, expectedOk, actual, actualOk)
+			}
+			delete(m, expected)
+		}
+		checkHeap(t, h, m)
+	}
+}
@@ -0,0 +1,120 @@
+// Package indexed provides a collection of records that is ordered by several indexes at the same time.
+package indexed
+
+import (
+	"github.com/Sora233/datastructure"
+	"github.com/Sora233/datastructure/bst/avl"
+	"github.com/Sora233/datastructure/compare"
+)
+
+// Collection is a set of records with a unique primary index and any number of secondary indexes.
+// Insert and Delete keep all indexes consistent.
+// The records should not be modified while they are in the collection, use Update instead.
+// It is not safe for concurrent use.
+type Collection[T any] struct {
+	primary     *Index[T]
+	secondaries []*Index[T]
+	names       map[string]*Index[T]
+}
+
+// New create a Collection whose records are unique by primary.
+func New[T any](primary compare.ICompare[T]) *Collection[T] {
+	return &Collection[T]{
+		primary: newIndex(primary, primary),
+		names:   make(map[string]*Index[T]),
+	}
+}
+
+// AddIndex adds a secondary index ordered by cmp, and indexes the existing records.
+// The records that cmp considers EQ are ordered by the primary index.
+// It panics if the name is used by another index.
+func (c *Collection[T]) AddIndex(name string, cmp compare.ICompare[T]) *Index[T] {
+	if _, exists := c.names[name]; exists {
+		panic("indexed: duplicate index " + name)
+	}
+	index := newIndex(cmp, compare.ThenBy(cmp, c.primary.cmp))
+	c.primary.tree.Range(func(record T) bool {
+		index.tree.Insert(record)
+		return true
+	})
+	c.secondaries = append(c.secondaries, index)
+	c.names[name] = index
+	return index
+}
+
+// Index return the secondary index of name, or nil if it does not exist.
+func (c *Collection[T]) Index(name string) *Index[T] {
+	return c.names[name]
+}
+
+// Primary return the primary index.
+func (c *Collection[T]) Primary() *Index[T] {
+	return c.primary
+}
+
+// Insert inserts record into all indexes.
+// If a record with the same primary key exists, it is replaced.
+// return the replaced record, or the zero value.
+func (c *Collection[T]) Insert(record T) (old T, replaced bool) {
+	old, replaced = c.primary.tree.Insert(record)
+	for _, index := range c.secondaries {
+		if replaced {
+			index.tree.Delete(old)
+		}
+		index.tree.Insert(record)
+	}
+	return
+}
+
+// Delete deletes the record that has the same primary key as record from all indexes.
+// return the deleted record and true if it exists.
+func (c *Collection[T]) Delete(record T) (old T, exists bool) {
+	old, exists = c.primary.tree.Delete(record)
+	if !exists {
+		return
+	}
+	for _, index := range c.secondaries {
+		index.tree.Delete(old)
+	}
+	return
+}
+
+// Update replaces the record that has the same primary key as record with the result of f,
+// and moves it in all indexes. The result of f may have a different primary key.
+// If a record with the new primary key exists, it is replaced.
+// return true if the record exists and is updated.
+func (c *Collection[T]) Update(record T, f datastructure.ModifyFunc[T]) (success bool) {
+	old, exists := c.Get(record)
+	if !exists {
+		return false
+	}
+	newRecord := f(old)
+	c.Delete(old)
+	c.Insert(newRecord)
+	return true
+}
+
+// Get return the record that has the same primary key as record.
+func (c *Collection[T]) Get(record T) (res T, exists bool) {
+	return c.primary.tree.Find(record)
+}
+
+// Len return the number of records.
+func (c *Collection[T]) Len() int {
+	return c.primary.tree.Size()
+}
+
+// Clear removes all records from all indexes.
+func (c *Collection[T]) Clear() {
+	c.primary.tree.Clear()
+	for _, index := range c.secondaries {
+		index.tree.Clear()
+	}
+}
+
+func newIndex[T any](cmp, order compare.ICompare[T]) *Index[T] {
+	return &Index[T]{
+		cmp:  cmp,
+		tree: avl.New(order),
+	}
+}
@@ -0,0 +1,114 @@
+package indexed
+
+import (
+	"testing"
+
+	"github.com/Sora233/datastructure/bst"
+	"github.com/Sora233/datastructure/compare"
+	"github.com/stretchr/testify/assert"
+)
+
+type user struct {
+	ID   int
+	Name string
+	Age  int
+}
+
+func ids(seq func(yield func(user) bool)) []int {
+	var res []int
+	seq(func(u user) bool {
+		res = append(res, u.ID)
+		return true
+	})
+	return res
+}
+
+func newUsers() (*Collection[user], *Index[user], *Index[user]) {
+	c := New(compare.By(func(u user) int { return u.ID }, compare.OrderedLessCompareF[int]()))
+	c.Insert(user{ID: 3, Name: "carol", Age: 30})
+	c.Insert(user{ID: 1, Name: "alice", Age: 30})
+	byName := c.AddIndex("name", compare.By(func(u user) string { return u.Name }, compare.OrderedLessCompareF[string]()))
+	byAge := c.AddIndex("age", compare.By(func(u user) int { return u.Age }, compare.OrderedLessCompareF[int]()))
+	c.Insert(user{ID: 2, Name: "bob", Age: 25})
+	c.Insert(user{ID: 4, Name: "alice", Age: 41})
+	return c, byName, byAge
+}
+
+func TestCollection(t *testing.T) {
+	c, byName, byAge := newUsers()
+	assert.Equal(t, 4, c.Len())
+	assert.Same(t, byName, c.Index("name"))
+	assert.Nil(t, c.Index("email"))
+	assert.Panics(t, func() {
+		c.AddIndex("age", compare.By(func(u user) int { return -u.Age }, compare.OrderedLessCompareF[int]()))
+	})
+
+	assert.Equal(t, []int{1, 2, 3, 4}, ids(c.Primary().All()))
+	assert.Equal(t, []int{1, 4, 2, 3}, ids(byName.All()))
+	assert.Equal(t, []int{2, 1, 3, 4}, ids(byAge.All()))
+	assert.Equal(t, []int{4, 3, 1, 2}, ids(byAge.Backward()))
+
+	u, ok := c.Get(user{ID: 2})
+	assert.True(t, ok)
+	assert.Equal(t, "bob", u.Name)
+
+	// replacing a record moves it in every index
+	old, replaced := c.Insert(user{ID: 2, Name: "zed", Age: 50})
+	assert.True(t, replaced)
+	assert.Equal(t, "bob", old.Name)
+	assert.Equal(t, []int{1, 4, 3, 2}, ids(byName.All()))
+	assert.Equal(t, []int{1, 3, 4, 2}, ids(byAge.All()))
+	assert.Equal(t, 4, byName.Len())
+
+	old, ok = c.Delete(user{ID: 1})
+	assert.True(t, ok)
+	assert.Equal(t, "alice", old.Name)
+	_, ok = c.Delete(user{ID: 1})
+	assert.False(t, ok)
+	assert.Equal(t, []int{4, 3, 2}, ids(byName.All()))
+	assert.Equal(t, []int{3, 4, 2}, ids(byAge.All()))
+
+	assert.True(t, c.Update(user{ID: 3}, func(old user) user {
+		old.ID = 5
+		old.Age = 20
+		return old
+	}))
+	assert.False(t, c.Update(user{ID: 3}, func(old user) user { panic("called on absent record") }))
+	assert.Equal(t, []int{2, 4, 5}, ids(c.Primary().All()))
+	assert.Equal(t, []int{5, 4, 2}, ids(byAge.All()))
+	assert.Equal(t, []int{4, 5, 2}, ids(byName.All()))
+
+	c.Clear()
+	assert.Equal(t, 0, c.Len())
+	assert.Equal(t, 0, byName.Len())
+	assert.Nil(t, ids(byAge.All()))
+}
+
+func TestIndexQuery(t *testing.T) {
+	c, byName, byAge := newUsers()
+	c.Insert(user{ID: 5, Name: "dave", Age: 30})
+
+	u, ok := byName.Find(user{Name: "alice"})
+	assert.True(t, ok)
+	assert.Equal(t, 1, u.ID)
+	_, ok = byName.Find(user{Name: "eve"})
+	assert.False(t, ok)
+	assert.Equal(t, []int{1, 4}, ids(byName.FindAll(user{Name: "alice"})))
+	assert.Equal(t, []int{1, 3, 5}, ids(byAge.FindAll(user{Age: 30})))
+	assert.Nil(t, ids(byAge.FindAll(user{Age: 31})))
+
+	assert.Equal(t, []int{1, 3, 5, 4}, ids(byAge.Range(bst.Excluded(user{Age: 25}), bst.Unbounded[user]())))
+	assert.Equal(t, []int{2, 1, 3, 5}, ids(byAge.Range(bst.Included(user{Age: 25}), bst.Included(user{Age: 30}))))
+	assert.Equal(t, []int{2}, ids(byAge.Range(bst.Unbounded[user](), bst.Excluded(user{Age: 30}))))
+
+	assert.Equal(t, 2, byAge.Rank(user{Age: 30}))
+	assert.Equal(t, 5, byAge.Rank(user{Age: 31}))
+	assert.Equal(t, 6, byAge.Rank(user{Age: 99}))
+	u, ok = byAge.RankNth(5)
+	assert.True(t, ok)
+	assert.Equal(t, 4, u.ID)
+	u, _ = byAge.Min()
+	assert.Equal(t, 2, u.ID)
+	u, _ = byName.Max()
+	assert.Equal(t, 5, u.ID)
+}
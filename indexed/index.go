@@ -0,0 +1,94 @@
+package indexed
+
+import (
+	"github.com/Sora233/datastructure"
+	"github.com/Sora233/datastructure/bst"
+	"github.com/Sora233/datastructure/bst/avl"
+	"github.com/Sora233/datastructure/compare"
+)
+
+// Index is a read-only ordered view of the records in a Collection.
+// The queries take a probe record, only the fields that the comparator of the index reads need to be set.
+type Index[T any] struct {
+	// cmp is the comparator of the index, the tree is ordered by cmp and then by the primary index.
+	cmp  compare.ICompare[T]
+	tree *avl.AVL[T]
+}
+
+// Len return the number of records.
+func (idx *Index[T]) Len() int {
+	return idx.tree.Size()
+}
+
+// Find return the first record that is EQ to probe.
+func (idx *Index[T]) Find(probe T) (res T, exists bool) {
+	res, exists = idx.tree.LowerBoundBy(idx.probe(probe))
+	if !exists || !idx.cmp.Compare(res, probe).EQ() {
+		var zero T
+		return zero, false
+	}
+	return
+}
+
+// FindAll return an iterator over the records that are EQ to probe.
+func (idx *Index[T]) FindAll(probe T) datastructure.Seq[T] {
+	return func(yield func(T) bool) {
+		idx.tree.RangeBy(idx.probe(probe), yield)
+	}
+}
+
+// Range return an iterator over the records in the range from lo to hi in ascending order.
+func (idx *Index[T]) Range(lo, hi bst.Bound[T]) datastructure.Seq[T] {
+	return func(yield func(T) bool) {
+		idx.tree.RangeBy(func(elem T) compare.Result {
+			if !lo.IsLowerOf(idx.cmp, elem) {
+				return compare.LT
+			}
+			if !hi.IsUpperOf(idx.cmp, elem) {
+				return compare.GT
+			}
+			return compare.EQ
+		}, yield)
+	}
+}
+
+// All return an iterator over all records in ascending order.
+func (idx *Index[T]) All() datastructure.Seq[T] {
+	return idx.tree.All()
+}
+
+// Backward return an iterator over all records in descending order.
+func (idx *Index[T]) Backward() datastructure.Seq[T] {
+	return idx.tree.Backward()
+}
+
+// Min return the minimum record.
+func (idx *Index[T]) Min() (res T, exists bool) {
+	return idx.tree.Min()
+}
+
+// Max return the maximum record.
+func (idx *Index[T]) Max() (res T, exists bool) {
+	return idx.tree.Max()
+}
+
+// Rank return the rank of probe in the index,
+// if the rank is N, it means there are (N-1) records that are smaller than probe.
+func (idx *Index[T]) Rank(probe T) int {
+	res, exists := idx.tree.LowerBoundBy(idx.probe(probe))
+	if !exists {
+		return idx.tree.Size() + 1
+	}
+	return idx.tree.Rank(res)
+}
+
+// RankNth return the record that has the rank-th value.
+func (idx *Index[T]) RankNth(rank int) (res T, exists bool) {
+	return idx.tree.RankNth(rank)
+}
+
+func (idx *Index[T]) probe(probe T) bst.Probe[T] {
+	return func(elem T) compare.Result {
+		return idx.cmp.Compare(elem, probe)
+	}
+}
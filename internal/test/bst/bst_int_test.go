@@ -5,6 +5,7 @@ import (
 	"github.com/Sora233/datastructure/bst/avl"
 	"github.com/Sora233/datastructure/bst/treap"
 	"github.com/Sora233/datastructure/compare"
+	"github.com/Sora233/datastructure/entry"
 	"github.com/stretchr/testify/suite"
 	"testing"
 )
@@ -211,3 +212,89 @@ func (s *BSTIntSuite) TestPrevAndNextAndFindOrNextAndFindOrPrev() {
 func TestBST(t *testing.T) {
 	suite.Run(t, new(BSTIntSuite))
 }
+
+func TestInvalidCompareResult(t *testing.T) {
+	cmp := compare.WithFunc[int](func(a, b int) compare.Result {
+		if a == b {
+			return compare.EQ
+		}
+		return 0
+	})
+	for _, tree := range []bst.MultisetTree[int]{avl.New[int](cmp), treap.New[int](cmp)} {
+		tree.Insert(1)
+		for name, op := range map[string]func(){
+			"Insert":     func() { tree.Insert(2) },
+			"Count":      func() { tree.Count(2) },
+			"Find":       func() { tree.Find(2) },
+			"Prev":       func() { tree.Prev(2) },
+			"Next":       func() { tree.Next(2) },
+			"FindOrNext": func() { tree.FindOrNext(2) },
+			"FindOrPrev": func() { tree.FindOrPrev(2) },
+			"RangeS":     func() { tree.RangeS(2, func(int) bool { return true }) },
+			"RangeE":     func() { tree.RangeE(2, func(int) bool { return true }) },
+		} {
+			func() {
+				defer func() {
+					err, ok := recover().(*compare.InconsistentError)
+					if !ok || err.Reason != "invalid result" || err.A != 1 || err.B != 2 {
+						t.Fatalf("%v: expected panic with invalid result, got %v", name, err)
+					}
+				}()
+				op()
+			}()
+		}
+	}
+}
+
+func TestInvalidProbeResult(t *testing.T) {
+	cmp := compare.OrderedLessCompareF[int]()
+	probe := func(int) compare.Result { return 0 }
+	for _, tree := range []bst.BinarySearchTree[int]{avl.New[int](cmp), treap.New[int](cmp)} {
+		tree.Insert(1)
+		for name, op := range map[string]func(){
+			"FindBy":       func() { tree.FindBy(probe) },
+			"LowerBoundBy": func() { tree.LowerBoundBy(probe) },
+			"UpperBoundBy": func() { tree.UpperBoundBy(probe) },
+			"RangeBy":      func() { tree.RangeBy(probe, func(int) bool { return true }) },
+		} {
+			func() {
+				defer func() {
+					err, ok := recover().(*compare.InconsistentError)
+					if !ok || err.Reason != "invalid probe result" || err.A != 1 || err.B != nil {
+						t.Fatalf("%v: expected panic with invalid probe result, got %v", name, err)
+					}
+					if msg := "compare: inconsistent comparator: invalid probe result: Result(0) for 1"; err.Error() != msg {
+						t.Fatalf("%v: expected error %q, got %q", name, msg, err.Error())
+					}
+				}()
+				op()
+			}()
+		}
+	}
+}
+
+func TestCountableMultisetTree(t *testing.T) {
+	cmp := entry.OrderedDuplicateLessCompareF[int]()
+	for _, tree := range []bst.MultisetTree[entry.Duplicate[int]]{avl.New(cmp), treap.New(cmp)} {
+		tree.Insert(entry.NewDuplicateCount(1, 5))
+		if c := tree.Count(entry.NewDuplicate(1)); c != 5 {
+			t.Fatalf("Count: expected 5, got %v", c)
+		}
+		for name, op := range map[string]func(){
+			"Add":    func() { tree.Add(entry.NewDuplicate(1), 3) },
+			"Remove": func() { tree.Remove(entry.NewDuplicate(1), 1) },
+		} {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Fatalf("%v: expected panic for bst.Countable elements", name)
+					}
+				}()
+				op()
+			}()
+		}
+		if tree.Size() != 5 {
+			t.Fatalf("Size: expected 5, got %v", tree.Size())
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package bst
+
+import (
+	"testing"
+
+	"github.com/Sora233/datastructure/allocator"
+	"github.com/Sora233/datastructure/bst"
+	"github.com/Sora233/datastructure/bst/avl"
+	"github.com/Sora233/datastructure/bst/bsttest"
+	"github.com/Sora233/datastructure/bst/treap"
+	"github.com/Sora233/datastructure/compare"
+	"github.com/Sora233/datastructure/entry"
+)
+
+func TestAVLConformance(t *testing.T) {
+	bsttest.Run(t, func(cmp compare.ICompare[bsttest.Element]) bst.BinarySearchTree[bsttest.Element] {
+		return avl.New(cmp)
+	})
+	bsttest.RunCountable(t, func(cmp compare.ICompare[entry.Duplicate[int]]) bst.BinarySearchTree[entry.Duplicate[int]] {
+		return avl.New(cmp)
+	})
+}
+
+func TestTreapConformance(t *testing.T) {
+	bsttest.Run(t, func(cmp compare.ICompare[bsttest.Element]) bst.BinarySearchTree[bsttest.Element] {
+		return treap.New(cmp)
+	})
+	bsttest.RunCountable(t, func(cmp compare.ICompare[entry.Duplicate[int]]) bst.BinarySearchTree[entry.Duplicate[int]] {
+		return treap.New(cmp)
+	})
+}
+
+func TestTreapSimpleAllocatorConformance(t *testing.T) {
+	bsttest.Run(t, func(cmp compare.ICompare[bsttest.Element]) bst.BinarySearchTree[bsttest.Element] {
+		return treap.New(cmp, treap.WithAllocator[bsttest.Element](allocator.NewSimpleAllocator[treap.Node[bsttest.Element]]()))
+	})
+}
+
+func TestMultisetConformance(t *testing.T) {
+	t.Run("AVL", func(t *testing.T) {
+		bsttest.RunMultiset(t, func(cmp compare.ICompare[int]) bst.MultisetTree[int] {
+			return avl.New(cmp, avl.WithMultiset[int]())
+		})
+	})
+	t.Run("treap", func(t *testing.T) {
+		bsttest.RunMultiset(t, func(cmp compare.ICompare[int]) bst.MultisetTree[int] {
+			return treap.New(cmp, treap.WithMultiset[int]())
+		})
+	})
+}
+
+func TestSafeIterationConformance(t *testing.T) {
+	t.Run("AVL", func(t *testing.T) {
+		bsttest.Run(t, func(cmp compare.ICompare[bsttest.Element]) bst.BinarySearchTree[bsttest.Element] {
+			return avl.New(cmp, avl.WithSafeIteration[bsttest.Element]())
+		})
+	})
+	t.Run("Treap", func(t *testing.T) {
+		bsttest.Run(t, func(cmp compare.ICompare[bsttest.Element]) bst.BinarySearchTree[bsttest.Element] {
+			return treap.New(cmp, treap.WithSafeIteration[bsttest.Element]())
+		})
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/Sora233/datastructure/bst"
 	"github.com/Sora233/datastructure/bst/avl"
 	"github.com/Sora233/datastructure/bst/treap"
+	"github.com/Sora233/datastructure/compare"
 	"github.com/Sora233/datastructure/entry"
 	"github.com/stretchr/testify/suite"
 	"io"
@@ -24,6 +25,10 @@ type BSTDataSuite struct {
 		name string
 		tree bst.BinarySearchTree[entry.Duplicate[int]]
 	}
+	multisetSet []struct {
+		name string
+		tree bst.BinarySearchTree[int]
+	}
 }
 
 func (s *BSTDataSuite) SetupTest() {
@@ -42,12 +47,31 @@ func (s *BSTDataSuite) SetupTest() {
 		name: "AVL",
 		tree: avl.New[entry.Duplicate[int]](entry.OrderedDuplicateLessCompareF[int]()),
 	})
+
+	s.multisetSet = append(s.multisetSet, struct {
+		name string
+		tree bst.BinarySearchTree[int]
+	}{
+		name: "treap-multiset",
+		tree: treap.New[int](compare.OrderedLessCompareF[int](), treap.WithMultiset[int]()),
+	})
+
+	s.multisetSet = append(s.multisetSet, struct {
+		name string
+		tree bst.BinarySearchTree[int]
+	}{
+		name: "AVL-multiset",
+		tree: avl.New[int](compare.OrderedLessCompareF[int](), avl.WithMultiset[int]()),
+	})
 }
 
 func (s *BSTDataSuite) TearDownSubTest() {
 	for _, t := range s.treeSet {
 		t.tree.Clear()
 	}
+	for _, t := range s.multisetSet {
+		t.tree.Clear()
+	}
 }
 
 func (s *BSTDataSuite) TestLOJ104() {
@@ -87,6 +111,13 @@ func (s *BSTDataSuite) TestLOJ104() {
 				}
 			})
 		}
+		for _, ts := range s.multisetSet {
+			s.Run(tc.name+"_"+ts.name, func() {
+				var result strings.Builder
+				s.runMultisetCase(ts.tree, strings.NewReader(tc.input), &result)
+				s.Equal(tc.output, result.String())
+			})
+		}
 	}
 }
 
@@ -127,6 +158,36 @@ func (s *BSTDataSuite) runCase(tree bst.BinarySearchTree[entry.Duplicate[int]],
 	}
 }
 
+func (s *BSTDataSuite) runMultisetCase(tree bst.BinarySearchTree[int], input io.Reader, w *strings.Builder) {
+	scanner := bufio.NewScanner(input)
+	scanner.Scan()
+	var n, op, x int
+	fmt.Sscanf(scanner.Text(), "%d", &n)
+	for ; n != 0; n-- {
+		scanner.Scan()
+		fmt.Sscanf(scanner.Text(), "%d %d", &op, &x)
+		var res int
+		switch op {
+		case 1:
+			tree.Insert(x)
+			continue
+		case 2:
+			tree.Delete(x)
+			continue
+		case 3:
+			res = tree.Rank(x)
+		case 4:
+			res, _ = tree.RankNth(x)
+		case 5:
+			res, _ = tree.Prev(x)
+		case 6:
+			res, _ = tree.Next(x)
+		}
+		w.WriteString(strconv.Itoa(res))
+		w.WriteRune('\n')
+	}
+}
+
 func TestBSTDataSuite(t *testing.T) {
 	suite.Run(t, new(BSTDataSuite))
 }
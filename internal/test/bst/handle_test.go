@@ -0,0 +1,122 @@
+package bst
+
+import (
+	"testing"
+
+	"github.com/Sora233/datastructure/bst"
+	"github.com/Sora233/datastructure/bst/avl"
+	"github.com/Sora233/datastructure/bst/treap"
+	"github.com/Sora233/datastructure/entry"
+	"github.com/stretchr/testify/assert"
+)
+
+type handle[T any] interface {
+	Valid() bool
+	Value() (T, bool)
+	Set(data T) bool
+}
+
+type handleTree[T any] struct {
+	bst.BinarySearchTree[T]
+	insertHandle func(data T) handle[T]
+	findHandle   func(data T) (handle[T], bool)
+}
+
+func newAVLHandleTree[T any](tree *avl.AVL[T]) handleTree[T] {
+	return handleTree[T]{
+		BinarySearchTree: tree,
+		insertHandle:     func(data T) handle[T] { return tree.InsertHandle(data) },
+		findHandle: func(data T) (handle[T], bool) {
+			return tree.FindHandle(data)
+		},
+	}
+}
+
+func newTreapHandleTree[T any](tree *treap.Treap[T]) handleTree[T] {
+	return handleTree[T]{
+		BinarySearchTree: tree,
+		insertHandle:     func(data T) handle[T] { return tree.InsertHandle(data) },
+		findHandle: func(data T) (handle[T], bool) {
+			return tree.FindHandle(data)
+		},
+	}
+}
+
+func testHandle(t *testing.T, tree handleTree[entry.KV[int, int]]) {
+	kv := entry.NewKV[int, int]
+	key := entry.Key[int, int]
+
+	h := tree.insertHandle(kv(50, 1))
+	v, ok := h.Value()
+	assert.True(t, ok)
+	assert.Equal(t, kv(50, 1), v)
+	for k := 0; k < 100; k++ {
+		tree.InsertOrIgnore(kv(k, k))
+	}
+	assert.True(t, h.Set(kv(50, 2)))
+	assert.False(t, h.Set(kv(51, 2)))
+	v, _ = tree.Find(key(50))
+	assert.Equal(t, kv(50, 2), v)
+
+	found, ok := tree.findHandle(key(50))
+	assert.True(t, ok)
+	v, _ = found.Value()
+	assert.Equal(t, kv(50, 2), v)
+	_, ok = tree.findHandle(key(100))
+	assert.False(t, ok)
+	tree.Insert(kv(50, 3))
+	v, _ = h.Value()
+	assert.Equal(t, kv(50, 3), v)
+
+	// deleting the other elements rotates the node of the handle around
+	for k := 0; k < 100; k++ {
+		if k != 50 {
+			tree.Delete(key(k))
+		}
+		assert.True(t, h.Valid())
+	}
+	tree.Delete(key(50))
+	assert.False(t, h.Valid())
+	assert.False(t, found.Valid())
+	_, ok = h.Value()
+	assert.False(t, ok)
+	assert.False(t, h.Set(kv(50, 4)))
+	_, ok = tree.Find(key(50))
+	assert.False(t, ok)
+
+	h = tree.insertHandle(kv(5, 5))
+	tree.Update(key(5), func(old entry.KV[int, int]) entry.KV[int, int] { return kv(6, old.Value) })
+	assert.False(t, h.Valid())
+	h, _ = tree.findHandle(key(6))
+	tree.Update(key(6), func(old entry.KV[int, int]) entry.KV[int, int] { return kv(6, 7) })
+	v, _ = h.Value()
+	assert.Equal(t, kv(6, 7), v)
+
+	tree.Clear()
+	assert.False(t, h.Valid())
+	tree.Insert(kv(6, 8))
+	assert.False(t, h.Valid())
+}
+
+func testCountableHandle(t *testing.T, tree handleTree[entry.Duplicate[int]]) {
+	tree.InsertOrVisit(entry.NewDuplicateCount(1, 2), entry.InsertDuplicate[int](2))
+	h := tree.insertHandle(entry.NewDuplicateCount(2, 3))
+	assert.Equal(t, 5, tree.Size())
+	assert.True(t, h.Set(entry.NewDuplicateCount(2, 10)))
+	assert.Equal(t, 12, tree.Size())
+	assert.Equal(t, 3, tree.Rank(entry.NewDuplicate(2)))
+}
+
+func TestHandle(t *testing.T) {
+	cmp := entry.OrderedKeyLessCompareF[int, int]()
+	dup := entry.OrderedDuplicateLessCompareF[int]()
+	t.Run("AVL", func(t *testing.T) { testHandle(t, newAVLHandleTree(avl.New(cmp))) })
+	t.Run("Treap", func(t *testing.T) { testHandle(t, newTreapHandleTree(treap.New(cmp))) })
+	t.Run("AVLCountable", func(t *testing.T) { testCountableHandle(t, newAVLHandleTree(avl.New(dup))) })
+	t.Run("TreapCountable", func(t *testing.T) { testCountableHandle(t, newTreapHandleTree(treap.New(dup))) })
+
+	var zero avl.Handle[int]
+	assert.False(t, zero.Valid())
+	_, ok := zero.Value()
+	assert.False(t, ok)
+}
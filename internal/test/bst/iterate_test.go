@@ -0,0 +1,71 @@
+package bst
+
+import (
+	"testing"
+
+	"github.com/Sora233/datastructure/bst"
+	"github.com/Sora233/datastructure/bst/avl"
+	"github.com/Sora233/datastructure/bst/treap"
+	"github.com/Sora233/datastructure/compare"
+	"github.com/stretchr/testify/assert"
+)
+
+func testSafeIteration(t *testing.T, tree bst.BinarySearchTree[int]) {
+	for i := 0; i < 100; i += 2 {
+		tree.Insert(i)
+	}
+	// the inserted element after the last visited one is visited, and the deleted one is skipped
+	var visited []int
+	tree.Range(func(e int) bool {
+		visited = append(visited, e)
+		if e%2 == 0 {
+			tree.Insert(e + 1)
+			tree.Delete(e + 2)
+		}
+		return true
+	})
+	var expected []int
+	for i := 0; i < 100; i += 4 {
+		expected = append(expected, i, i+1)
+	}
+	assert.Equal(t, expected, visited)
+	assert.Equal(t, 50, tree.Size())
+
+	visited = nil
+	tree.Backward()(func(e int) bool {
+		visited = append(visited, e)
+		tree.Delete(e - 1)
+		return true
+	})
+	expected = nil
+	for i := 97; i >= 0; i -= 4 {
+		expected = append(expected, i)
+	}
+	assert.Equal(t, expected, visited)
+	assert.Equal(t, 25, tree.Size())
+
+	visited = nil
+	tree.RangeSE(11, 21, func(e int) bool {
+		visited = append(visited, e)
+		tree.Clear()
+		return true
+	})
+	assert.Equal(t, []int{13}, visited)
+	assert.True(t, tree.Empty())
+}
+
+func TestSafeIteration(t *testing.T) {
+	cmp := compare.OrderedLessCompareF[int]()
+	t.Run("AVL", func(t *testing.T) { testSafeIteration(t, avl.New(cmp, avl.WithSafeIteration[int]())) })
+	t.Run("Treap", func(t *testing.T) { testSafeIteration(t, treap.New(cmp, treap.WithSafeIteration[int]())) })
+
+	tree := avl.New(cmp)
+	tree.Insert(1)
+	tree.Insert(2)
+	assert.PanicsWithValue(t, bst.ErrModifiedDuringIteration, func() {
+		tree.Range(func(e int) bool {
+			tree.Insert(e + 10)
+			return true
+		})
+	})
+}
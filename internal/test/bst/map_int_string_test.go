@@ -20,6 +20,17 @@ func randString(n int) string {
 	return string(b)
 }
 
+// mapOps is the subset of treemap.TreeMap exercised by the fuzzy test,
+// so that the builtin map can act as the reference implementation.
+type mapOps[K any, V any] interface {
+	Put(key K, value V) (old V, replaced bool)
+	PutIfAbsent(key K, value V) (success bool)
+	Get(key K) (value V, exists bool)
+	Delete(key K) (value V, exists bool)
+	Len() int
+	Clear()
+}
+
 type stdMap[K comparable, V any] struct {
 	m map[K]V
 }
@@ -57,7 +68,7 @@ func (s *stdMap[K, V]) Clear() {
 	s.m = make(map[K]V)
 }
 
-func newStdMap[K compare.Ordered, V any]() treemap.TreeMap[K, V] {
+func newStdMap[K compare.Ordered, V any]() mapOps[K, V] {
 	return &stdMap[K, V]{
 		m: make(map[K]V),
 	}
@@ -65,7 +76,7 @@ func newStdMap[K compare.Ordered, V any]() treemap.TreeMap[K, V] {
 
 type MapIntStringSuite struct {
 	suite.Suite
-	maps   []treemap.TreeMap[int, string]
+	maps   []mapOps[int, string]
 	maxKey []int
 	N      int
 }
@@ -90,7 +101,7 @@ type op[K compare.Ordered, V any] struct {
 	value V
 }
 
-func (o *op[K, V]) do(m treemap.TreeMap[K, V]) (any, any) {
+func (o *op[K, V]) do(m mapOps[K, V]) (any, any) {
 	switch o.op {
 	case "get":
 		a1, a2 := m.Get(o.key)
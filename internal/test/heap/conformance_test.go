@@ -0,0 +1,16 @@
+package heap
+
+import (
+	"testing"
+
+	"github.com/Sora233/datastructure/compare"
+	"github.com/Sora233/datastructure/heap"
+	"github.com/Sora233/datastructure/heap/binaryheap"
+	"github.com/Sora233/datastructure/heap/heaptest"
+)
+
+func TestBinaryHeapConformance(t *testing.T) {
+	heaptest.Run(t, func(cmp compare.ICompare[int]) heap.Heap[int] {
+		return binaryheap.New(cmp)
+	})
+}
@@ -0,0 +1,21 @@
+//go:build go1.24
+
+package datastructure
+
+import "iter"
+
+// Seq is an iterator over sequences of individual values, it is the same type as iter.Seq.
+type Seq[V any] = iter.Seq[V]
+
+// Seq2 is an iterator over sequences of pairs of values, it is the same type as iter.Seq2.
+type Seq2[K, V any] = iter.Seq2[K, V]
+
+// Pull converts the "push-style" iterator sequence seq into a "pull-style" iterator, see iter.Pull.
+func Pull[V any](seq Seq[V]) (next func() (V, bool), stop func()) {
+	return iter.Pull(seq)
+}
+
+// Pull2 converts the "push-style" iterator sequence seq into a "pull-style" iterator, see iter.Pull2.
+func Pull2[K, V any](seq Seq2[K, V]) (next func() (K, V, bool), stop func()) {
+	return iter.Pull2(seq)
+}
@@ -0,0 +1,94 @@
+//go:build !go1.24
+
+package datastructure
+
+// Seq is an iterator over sequences of individual values.
+// It has the same underlying type as iter.Seq, which it becomes an alias of since go1.24.
+type Seq[V any] func(yield func(V) bool)
+
+// Seq2 is an iterator over sequences of pairs of values.
+// It has the same underlying type as iter.Seq2, which it becomes an alias of since go1.24.
+type Seq2[K, V any] func(yield func(K, V) bool)
+
+// Pull converts the "push-style" iterator sequence seq into a "pull-style" iterator.
+// next returns the next value and true, or the zero value and false if the sequence is over.
+// stop ends the iteration, it must be called when the caller is no longer interested in next values.
+// Before go1.24 the sequence runs in a separate goroutine, which leaks if the sequence is not over and stop is not called.
+// If the sequence panics, the panic is propagated to the caller of next or stop.
+func Pull[V any](seq Seq[V]) (next func() (V, bool), stop func()) {
+	next2, stop := Pull2[V, struct{}](func(yield func(V, struct{}) bool) {
+		seq(func(v V) bool {
+			return yield(v, struct{}{})
+		})
+	})
+	next = func() (V, bool) {
+		v, _, ok := next2()
+		return v, ok
+	}
+	return next, stop
+}
+
+// Pull2 converts the "push-style" iterator sequence seq into a "pull-style" iterator.
+// next returns the next pair and true, or the zero values and false if the sequence is over.
+// stop ends the iteration, it must be called when the caller is no longer interested in next values.
+// Before go1.24 the sequence runs in a separate goroutine, which leaks if the sequence is not over and stop is not called.
+// If the sequence panics, the panic is propagated to the caller of next or stop.
+func Pull2[K, V any](seq Seq2[K, V]) (next func() (K, V, bool), stop func()) {
+	type pair struct {
+		k K
+		v V
+	}
+	var (
+		values = make(chan pair)
+		resume = make(chan bool)
+		done   = make(chan struct{})
+		over   bool
+		// panicked and panicValue are written before done is closed
+		panicked   bool
+		panicValue any
+	)
+	go func() {
+		defer close(done)
+		returned := false
+		defer func() {
+			if !returned {
+				panicked, panicValue = true, recover()
+			}
+		}()
+		if <-resume {
+			seq(func(k K, v V) bool {
+				values <- pair{k, v}
+				return <-resume
+			})
+		}
+		returned = true
+	}()
+	next = func() (k K, v V, ok bool) {
+		if over {
+			return
+		}
+		resume <- true
+		select {
+		case p := <-values:
+			return p.k, p.v, true
+		case <-done:
+			over = true
+			if panicked {
+				panic(panicValue)
+			}
+			return
+		}
+	}
+	stop = func() {
+		if over {
+			return
+		}
+		over = true
+		resume <- false
+		<-done
+		if panicked {
+			panic(panicValue)
+		}
+	}
+	return next, stop
+}
@@ -0,0 +1,78 @@
+package datastructure_test
+
+import (
+	"testing"
+
+	"github.com/Sora233/datastructure"
+	"github.com/Sora233/datastructure/bst"
+	"github.com/Sora233/datastructure/treemap"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPull(t *testing.T) {
+	m := treemap.NewMap[int, string]()
+	m.Put(2, "b")
+	m.Put(1, "a")
+	m.Put(3, "c")
+
+	next, stop := datastructure.Pull(m.KeySet())
+	k, ok := next()
+	assert.True(t, ok)
+	assert.Equal(t, 1, k)
+	k, ok = next()
+	assert.True(t, ok)
+	assert.Equal(t, 2, k)
+	stop()
+	_, ok = next()
+	assert.False(t, ok)
+	stop()
+
+	next2, stop2 := datastructure.Pull2(m.Items())
+	defer stop2()
+	var keys []int
+	var values []string
+	for {
+		k, v, ok := next2()
+		if !ok {
+			break
+		}
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	assert.Equal(t, []int{1, 2, 3}, keys)
+	assert.Equal(t, []string{"a", "b", "c"}, values)
+	_, _, ok = next2()
+	assert.False(t, ok)
+}
+
+func TestPullPanic(t *testing.T) {
+	next, stop := datastructure.Pull[int](func(yield func(int) bool) {
+		if yield(1) {
+			panic("boom")
+		}
+	})
+	v, ok := next()
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	assert.PanicsWithValue(t, "boom", func() { next() })
+	_, ok = next()
+	assert.False(t, ok)
+	stop()
+
+	next, stop = datastructure.Pull[int](func(yield func(int) bool) {
+		if !yield(1) {
+			panic("stopped")
+		}
+	})
+	next()
+	assert.PanicsWithValue(t, "stopped", stop)
+
+	m := treemap.NewMap[int, string]()
+	m.Put(1, "a")
+	m.Put(2, "b")
+	next, stop = datastructure.Pull(m.KeySet())
+	defer stop()
+	next()
+	m.Put(3, "c")
+	assert.PanicsWithValue(t, bst.ErrModifiedDuringIteration, func() { next() })
+}
@@ -11,3 +11,9 @@ type ConditionFunc[T any] func(T) bool
 // ModifyFunc is the function to modify the data
 // NOTE: This callback should not change the old data directly, make a copy of old and modify the copy instead.
 type ModifyFunc[T any] func(old T) (new T)
+
+// ComputeFunc is the function to compute the new data from the old data
+// exists is false if there is no old data, and old is the zero value.
+// return keep false to delete the old data, or not to insert when there is no old data.
+// NOTE: This callback should not change the old data directly, and the new data must have the same compare key.
+type ComputeFunc[T any] func(old T, exists bool) (new T, keep bool)
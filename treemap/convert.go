@@ -0,0 +1,62 @@
+package treemap
+
+import (
+	"github.com/Sora233/datastructure"
+	"github.com/Sora233/datastructure/compare"
+)
+
+// FromMap create a TreeMap that contains the entries of m.
+func FromMap[K compare.Ordered, V any](m map[K]V, opts ...OptionFunc[K, V]) TreeMap[K, V] {
+	return FromMapWithCompare(m, compare.OrderedLessCompareF[K](), opts...)
+}
+
+// FromMapWithCompare create a TreeMap ordered by keyCompare that contains the entries of m.
+func FromMapWithCompare[K comparable, V any](m map[K]V, keyCompare compare.ICompare[K], opts ...OptionFunc[K, V]) TreeMap[K, V] {
+	res := NewMapWithCompare[K, V](keyCompare, opts...)
+	for k, v := range m {
+		res.Put(k, v)
+	}
+	return res
+}
+
+// Collect create a TreeMap that contains the pairs yielded by seq, such as the Items of another map.
+// The later value wins if a key is yielded more than once.
+func Collect[K compare.Ordered, V any](seq datastructure.Seq2[K, V], opts ...OptionFunc[K, V]) TreeMap[K, V] {
+	return CollectWithCompare(seq, compare.OrderedLessCompareF[K](), opts...)
+}
+
+// CollectWithCompare create a TreeMap ordered by keyCompare that contains the pairs yielded by seq.
+// The later value wins if a key is yielded more than once.
+func CollectWithCompare[K any, V any](seq datastructure.Seq2[K, V], keyCompare compare.ICompare[K], opts ...OptionFunc[K, V]) TreeMap[K, V] {
+	res := NewMapWithCompare[K, V](keyCompare, opts...)
+	seq(func(k K, v V) bool {
+		res.Put(k, v)
+		return true
+	})
+	return res
+}
+
+// ToMap return a builtin map that contains the entries of m.
+func ToMap[K comparable, V any](m TreeMap[K, V]) map[K]V {
+	res := make(map[K]V, m.Len())
+	m.Items()(func(k K, v V) bool {
+		res[k] = v
+		return true
+	})
+	return res
+}
+
+// Equal return true if a and b contain the same keys, and valueEq returns true for the values of every key.
+// The keys are compared by b.
+func Equal[K any, V any](a, b TreeMap[K, V], valueEq func(a, b V) bool) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	res := true
+	a.Items()(func(k K, v V) bool {
+		other, exists := b.Get(k)
+		res = exists && valueEq(v, other)
+		return res
+	})
+	return res
+}
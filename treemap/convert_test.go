@@ -0,0 +1,37 @@
+package treemap
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Sora233/datastructure/compare"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvert(t *testing.T) {
+	src := map[string]int{"b": 2, "a": 1, "c": 3}
+	m := FromMap(src)
+	assert.Equal(t, []string{"a", "b", "c"}, m.Keys())
+	assert.Equal(t, []int{1, 2, 3}, m.Values())
+	assert.Equal(t, src, ToMap(m))
+
+	c := Collect(m.Items())
+	assert.True(t, Equal(m, c, func(a, b int) bool { return a == b }))
+	c.Put("b", 20)
+	assert.False(t, Equal(m, c, func(a, b int) bool { return a == b }))
+	assert.True(t, Equal(m, c, func(a, b int) bool { return a%10 == b%10 || a*10 == b }))
+	c.Delete("b")
+	assert.False(t, Equal(m, c, func(a, b int) bool { return true }))
+	c.Put("d", 2)
+	assert.False(t, Equal(m, c, func(a, b int) bool { return true }))
+
+	fold := compare.FoldLessCompareF[string]()
+	f := FromMapWithCompare(map[string]int{"B": 2, "a": 1}, fold)
+	assert.Equal(t, []string{"a", "B"}, f.Keys())
+	f = CollectWithCompare(m.Items(), fold)
+	v, _ := f.Get(strings.ToUpper("c"))
+	assert.Equal(t, 3, v)
+
+	assert.Equal(t, []string{"b"}, m.SubMap("b", "c", IncludeFrom).Keys())
+	assert.Empty(t, NewMap[int, int]().Values())
+}
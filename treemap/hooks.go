@@ -0,0 +1,57 @@
+package treemap
+
+// Hooks observes the changes of a TreeMap.
+// Each hook is called after the mutation succeeds, a nil hook is skipped.
+// The hooks must not modify the map.
+type Hooks[K any, V any] struct {
+	// OnInsert is called when key is inserted with value.
+	OnInsert func(key K, value V)
+	// OnReplace is called when the value of key is replaced from old to value.
+	OnReplace func(key K, old, value V)
+	// OnDelete is called when key is deleted, old is its last value.
+	OnDelete func(key K, old V)
+	// OnClear is called when the whole map is cleared.
+	// Clearing a view calls OnDelete for each deleted key instead.
+	OnClear func()
+}
+
+// hookList is shared by a map and its views.
+type hookList[K any, V any] struct {
+	hooks []Hooks[K, V]
+}
+
+func (t *treeMap[K, V]) AddHooks(hooks Hooks[K, V]) {
+	t.hooks.hooks = append(t.hooks.hooks, hooks)
+}
+
+func (t *treeMap[K, V]) onInsert(key K, value V) {
+	for _, h := range t.hooks.hooks {
+		if h.OnInsert != nil {
+			h.OnInsert(key, value)
+		}
+	}
+}
+
+func (t *treeMap[K, V]) onReplace(key K, old, value V) {
+	for _, h := range t.hooks.hooks {
+		if h.OnReplace != nil {
+			h.OnReplace(key, old, value)
+		}
+	}
+}
+
+func (t *treeMap[K, V]) onDelete(key K, old V) {
+	for _, h := range t.hooks.hooks {
+		if h.OnDelete != nil {
+			h.OnDelete(key, old)
+		}
+	}
+}
+
+func (t *treeMap[K, V]) onClear() {
+	for _, h := range t.hooks.hooks {
+		if h.OnClear != nil {
+			h.OnClear()
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package treemap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHooks(t *testing.T) {
+	m := NewMap[int, string]()
+	var log []string
+	m.AddHooks(Hooks[int, string]{
+		OnInsert:  func(k int, v string) { log = append(log, fmt.Sprint("insert ", k, " ", v)) },
+		OnReplace: func(k int, old, v string) { log = append(log, fmt.Sprint("replace ", k, " ", old, " ", v)) },
+		OnDelete:  func(k int, old string) { log = append(log, fmt.Sprint("delete ", k, " ", old)) },
+		OnClear:   func() { log = append(log, "clear") },
+	})
+	index := map[string]int{}
+	m.AddHooks(Hooks[int, string]{
+		OnInsert: func(k int, v string) { index[v] = k },
+	})
+
+	m.Put(1, "a")
+	m.Put(1, "b")
+	m.PutIfAbsent(1, "c")
+	m.PutIfAbsent(2, "c")
+	m.Delete(3)
+	m.Delete(2)
+	m.Compute(3, func(old string, exists bool) (string, bool) { return "d", true })
+	m.Compute(3, func(old string, exists bool) (string, bool) { return old + "e", true })
+	m.Compute(3, func(old string, exists bool) (string, bool) { return "", false })
+	m.Compute(4, func(old string, exists bool) (string, bool) { return "", false })
+	m.Merge(1, "x", func(old, value string) string { return old + value })
+	m.Put(5, "f")
+	m.PollLast()
+	m.Put(6, "g")
+	m.Put(7, "h")
+	m.TailMap(6).Clear()
+	m.Clear()
+	assert.Equal(t, []string{
+		"insert 1 a",
+		"replace 1 a b",
+		"insert 2 c",
+		"delete 2 c",
+		"insert 3 d",
+		"replace 3 d de",
+		"delete 3 de",
+		"replace 1 b bx",
+		"insert 5 f",
+		"delete 5 f",
+		"insert 6 g",
+		"insert 7 h",
+		"delete 6 g",
+		"delete 7 h",
+		"clear",
+	}, log)
+	assert.Equal(t, map[string]int{"a": 1, "c": 2, "d": 3, "f": 5, "g": 6, "h": 7}, index)
+}
@@ -0,0 +1,237 @@
+package treemap
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// KeyCodec converts the keys of a TreeMap to and from JSON object keys.
+type KeyCodec[K any] interface {
+	EncodeKey(key K) (string, error)
+	DecodeKey(s string) (key K, err error)
+}
+
+type keyCodecFunc[K any] struct {
+	encode func(K) (string, error)
+	decode func(string) (K, error)
+}
+
+func (c keyCodecFunc[K]) EncodeKey(key K) (string, error) {
+	return c.encode(key)
+}
+
+func (c keyCodecFunc[K]) DecodeKey(s string) (K, error) {
+	return c.decode(s)
+}
+
+// NewKeyCodec create a KeyCodec from a pair of functions.
+func NewKeyCodec[K any](encode func(K) (string, error), decode func(string) (K, error)) KeyCodec[K] {
+	return keyCodecFunc[K]{encode: encode, decode: decode}
+}
+
+var (
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// defaultKeyCodec return the codec for the key types that encoding/json accepts as map keys:
+// string kinds, integer kinds and encoding.TextMarshaler.
+// It returns nil for any other key type.
+func defaultKeyCodec[K any]() KeyCodec[K] {
+	typ := reflect.TypeOf((*K)(nil)).Elem()
+	switch typ.Kind() {
+	case reflect.String:
+		return NewKeyCodec[K](
+			func(key K) (string, error) {
+				return reflect.ValueOf(&key).Elem().String(), nil
+			},
+			func(s string) (key K, err error) {
+				reflect.ValueOf(&key).Elem().SetString(s)
+				return
+			},
+		)
+	}
+	if typ.Implements(textMarshalerType) && reflect.PointerTo(typ).Implements(textUnmarshalerType) {
+		return NewKeyCodec[K](
+			func(key K) (string, error) {
+				b, err := any(key).(encoding.TextMarshaler).MarshalText()
+				return string(b), err
+			},
+			func(s string) (key K, err error) {
+				err = any(&key).(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+				return
+			},
+		)
+	}
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return NewKeyCodec[K](
+			func(key K) (string, error) {
+				return strconv.FormatInt(reflect.ValueOf(&key).Elem().Int(), 10), nil
+			},
+			func(s string) (key K, err error) {
+				v := reflect.ValueOf(&key).Elem()
+				n, err := strconv.ParseInt(s, 10, typ.Bits())
+				if err != nil {
+					return
+				}
+				v.SetInt(n)
+				return
+			},
+		)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return NewKeyCodec[K](
+			func(key K) (string, error) {
+				return strconv.FormatUint(reflect.ValueOf(&key).Elem().Uint(), 10), nil
+			},
+			func(s string) (key K, err error) {
+				v := reflect.ValueOf(&key).Elem()
+				n, err := strconv.ParseUint(s, 10, typ.Bits())
+				if err != nil {
+					return
+				}
+				v.SetUint(n)
+				return
+			},
+		)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the map as a JSON object whose keys appear in tree order.
+// If the keys cannot be converted to strings, the map is encoded as an array of [key, value] pairs instead.
+func (t *treeMap[K, V]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if t.keyCodec != nil {
+		buf.WriteByte('{')
+		t.Items()(func(key K, value V) bool {
+			var s string
+			if s, err = t.keyCodec.EncodeKey(key); err != nil {
+				return false
+			}
+			if err = writeJSON(&buf, s, buf.Len() > 1); err != nil {
+				return false
+			}
+			buf.WriteByte(':')
+			err = writeJSON(&buf, value, false)
+			return err == nil
+		})
+		buf.WriteByte('}')
+	} else {
+		buf.WriteByte('[')
+		t.Items()(func(key K, value V) bool {
+			if buf.Len() > 1 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('[')
+			if err = writeJSON(&buf, key, false); err != nil {
+				return false
+			}
+			err = writeJSON(&buf, value, true)
+			buf.WriteByte(']')
+			return err == nil
+		})
+		buf.WriteByte(']')
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes either a JSON object or an array of [key, value] pairs into the map.
+// The decoded entries are added to the map, existing keys are overwritten.
+// Decoding into a view returns an error for the keys out of range of the view.
+func (t *treeMap[K, V]) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return fmt.Errorf("treemap: unexpected end of JSON input")
+	}
+	switch data[0] {
+	case 'n':
+		return json.Unmarshal(data, new(any))
+	case '{':
+		return t.unmarshalObject(data)
+	case '[':
+		return t.unmarshalPairs(data)
+	default:
+		return fmt.Errorf("treemap: cannot unmarshal %q into a TreeMap", data)
+	}
+}
+
+func (t *treeMap[K, V]) unmarshalObject(data []byte) error {
+	if t.keyCodec == nil {
+		var key K
+		return fmt.Errorf("treemap: cannot unmarshal JSON object key into %T without a KeyCodec", key)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, err := t.keyCodec.DecodeKey(tok.(string))
+		if err != nil {
+			return err
+		}
+		var value V
+		if err = dec.Decode(&value); err != nil {
+			return err
+		}
+		if err = t.putDecoded(key, value); err != nil {
+			return err
+		}
+	}
+	_, err := dec.Token()
+	return err
+}
+
+func (t *treeMap[K, V]) unmarshalPairs(data []byte) error {
+	var pairs [][2]json.RawMessage
+	if err := json.Unmarshal(data, &pairs); err != nil {
+		return err
+	}
+	for _, pair := range pairs {
+		var key K
+		var value V
+		if err := json.Unmarshal(pair[0], &key); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(pair[1], &value); err != nil {
+			return err
+		}
+		if err := t.putDecoded(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// putDecoded puts a decoded entry, the keys out of range of the view are reported as an error instead of panic.
+func (t *treeMap[K, V]) putDecoded(key K, value V) error {
+	if !t.inRange(key) {
+		return fmt.Errorf("treemap: key %v out of range of the view", key)
+	}
+	t.Put(key, value)
+	return nil
+}
+
+func writeJSON(buf *bytes.Buffer, v any, comma bool) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if comma {
+		buf.WriteByte(',')
+	}
+	buf.Write(b)
+	return nil
+}
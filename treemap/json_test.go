@@ -0,0 +1,89 @@
+package treemap
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	m := NewMap[string, int]()
+	m.Put("c", 3)
+	m.Put("a", 1)
+	m.Put("b", 2)
+	b, err := json.Marshal(m)
+	require.Nil(t, err)
+	assert.Equal(t, `{"a":1,"b":2,"c":3}`, string(b))
+
+	n := NewMap[int, string]()
+	n.Put(10, "x")
+	n.Put(-2, "y")
+	b, err = json.Marshal(n)
+	require.Nil(t, err)
+	assert.Equal(t, `{"-2":"y","10":"x"}`, string(b))
+
+	f := NewMap[float64, bool]()
+	f.Put(1.5, true)
+	f.Put(0.5, false)
+	b, err = json.Marshal(f)
+	require.Nil(t, err)
+	assert.Equal(t, `[[0.5,false],[1.5,true]]`, string(b))
+
+	e := NewMap[float64, bool]()
+	b, err = json.Marshal(e)
+	require.Nil(t, err)
+	assert.Equal(t, `[]`, string(b))
+}
+
+func TestMarshalJSONWithKeyCodec(t *testing.T) {
+	codec := NewKeyCodec[float64](
+		func(key float64) (string, error) {
+			return strconv.FormatFloat(key, 'g', -1, 64), nil
+		},
+		func(s string) (float64, error) {
+			return strconv.ParseFloat(s, 64)
+		},
+	)
+	m := NewMap[float64, int](WithKeyCodec[float64, int](codec))
+	m.Put(2.5, 2)
+	m.Put(1, 1)
+	b, err := json.Marshal(m)
+	require.Nil(t, err)
+	assert.Equal(t, `{"1":1,"2.5":2}`, string(b))
+
+	m2 := NewMap[float64, int](WithKeyCodec[float64, int](codec))
+	require.Nil(t, json.Unmarshal(b, m2))
+	assert.Equal(t, 2, m2.Len())
+	v, ok := m2.Get(2.5)
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	m := NewMap[int, string]()
+	require.Nil(t, json.Unmarshal([]byte(`{"3":"c","1":"a"}`), m))
+	require.Nil(t, json.Unmarshal([]byte(`[[2,"b"],[3,"cc"]]`), m))
+	var keys []int
+	var values []string
+	m.Items()(func(k int, v string) bool {
+		keys = append(keys, k)
+		values = append(values, v)
+		return true
+	})
+	assert.Equal(t, []int{1, 2, 3}, keys)
+	assert.Equal(t, []string{"a", "b", "cc"}, values)
+
+	f := NewMap[float64, int]()
+	assert.NotNil(t, json.Unmarshal([]byte(`{"1":1}`), f))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"x":1}`), m))
+	assert.NotNil(t, json.Unmarshal([]byte(`"x"`), m))
+
+	head := m.HeadMap(5)
+	require.Nil(t, json.Unmarshal([]byte(`{"4":"d"}`), head))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"7":"g"}`), head))
+	assert.NotNil(t, json.Unmarshal([]byte(`[[5,"e"]]`), head))
+	assert.Equal(t, 4, m.Len())
+}
@@ -1,6 +1,7 @@
 package treemap
 
 import (
+	"github.com/Sora233/datastructure"
 	"github.com/Sora233/datastructure/bst"
 	"github.com/Sora233/datastructure/bst/avl"
 	"github.com/Sora233/datastructure/compare"
@@ -14,89 +15,393 @@ type TreeMap[K any, V any] interface {
 	PutIfAbsent(key K, value V) (success bool)
 	Get(key K) (value V, exists bool)
 	Delete(key K) (value V, exists bool)
+	// Compute computes the value of key with f in a single descent.
+	// f is called with the old value and true, or the zero value and false if key does not exist.
+	// If f returns keep false, key is deleted.
+	// return the value of key after the computation, and true if it exists.
+	Compute(key K, f func(old V, exists bool) (value V, keep bool)) (value V, exists bool)
+	// ComputeIfAbsent stores the value returned by f if key does not exist.
+	// return the value of key after the computation.
+	ComputeIfAbsent(key K, f func() V) (value V)
+	// ComputeIfPresent computes the value of key with f if key exists.
+	// If f returns keep false, key is deleted.
+	// return the value of key after the computation, and true if it exists.
+	ComputeIfPresent(key K, f func(old V) (value V, keep bool)) (value V, exists bool)
+	// Merge stores value if key does not exist, or the result of f with the old value and value.
+	// return the value of key after the merge.
+	Merge(key K, value V, f func(old, value V) V) (res V)
 	Len() int
 	Clear()
-	KeySet() func(yield func(K) bool)
-	Items() func(yield func(K, V) bool)
+	KeySet() datastructure.Seq[K]
+	Items() datastructure.Seq2[K, V]
+	// Keys return the keys in ascending order.
+	Keys() []K
+	// Values return the values in ascending order of keys.
+	Values() []V
+
+	// FirstEntry return the entry with the minimum key.
+	FirstEntry() (e entry.KV[K, V], exists bool)
+	// LastEntry return the entry with the maximum key.
+	LastEntry() (e entry.KV[K, V], exists bool)
+	// FloorEntry return the entry with the maximum key K that satisfies K <= key.
+	FloorEntry(key K) (e entry.KV[K, V], exists bool)
+	// CeilingEntry return the entry with the minimum key K that satisfies K >= key.
+	CeilingEntry(key K) (e entry.KV[K, V], exists bool)
+	// LowerEntry return the entry with the maximum key K that satisfies K < key.
+	LowerEntry(key K) (e entry.KV[K, V], exists bool)
+	// HigherEntry return the entry with the minimum key K that satisfies K > key.
+	HigherEntry(key K) (e entry.KV[K, V], exists bool)
+	// PollFirst deletes and return the entry with the minimum key.
+	PollFirst() (e entry.KV[K, V], exists bool)
+	// PollLast deletes and return the entry with the maximum key.
+	PollLast() (e entry.KV[K, V], exists bool)
+
+	// At return the entry at index i in ascending order of keys, the index starts from 0.
+	// If i is out of range, return the zero value and false.
+	At(i int) (key K, value V, exists bool)
+	// IndexOf return the index of key in ascending order of keys, or -1 if key does not exist.
+	IndexOf(key K) int
+	// RangeByIndex iterate over the entries whose index is in [from, to) in ascending order of keys.
+	// The indices are clamped to [0, Len()].
+	RangeByIndex(from, to int) datastructure.Seq2[K, V]
+
+	// Range return an iterator over the entries whose keys are in the range from lo to hi in ascending order,
+	// whether each end is included is determined by the bst.Bound.
+	Range(lo, hi bst.Bound[K]) datastructure.Seq2[K, V]
+
+	// AddHooks registers hooks that observe the changes of the map and all of its views.
+	AddHooks(hooks Hooks[K, V])
+
+	// Begin starts a transaction that stages writes to the map until Commit.
+	// The transaction of a view can only write the keys in its range.
+	Begin() Tx[K, V]
+
+	// SubMap return a view of the portion of the map whose keys range from from to to,
+	// inclusivity determines whether from and to are included.
+	// The view is backed by the map, so changes in either are reflected in the other.
+	// Writing a key out of the range of the view panics.
+	// The view of a view covers the intersection of both ranges.
+	SubMap(from, to K, inclusivity Inclusivity) TreeMap[K, V]
+	// HeadMap return a view of the portion of the map whose keys are less than to.
+	HeadMap(to K) TreeMap[K, V]
+	// TailMap return a view of the portion of the map whose keys are greater than or equal to from.
+	TailMap(from K) TreeMap[K, V]
 }
 
 type treeMap[K any, V any] struct {
-	tree bst.BinarySearchTree[entry.KV[K, V]]
+	tree     bst.BinarySearchTree[entry.KV[K, V]]
+	keyCodec KeyCodec[K]
+	// lo and hi are the key range of a view, they are unbounded for the map itself.
+	lo, hi bst.Bound[K]
+	hooks  *hookList[K, V]
 }
 
 func (t *treeMap[K, V]) Put(key K, value V) (old V, replaced bool) {
+	t.checkRange(key)
 	e, replaced := t.tree.Insert(entry.NewKV(key, value))
 	old = e.Value
+	if replaced {
+		t.onReplace(key, old, value)
+	} else {
+		t.onInsert(key, value)
+	}
 	return
 }
 
 func (t *treeMap[K, V]) PutIfAbsent(key K, value V) (success bool) {
+	t.checkRange(key)
 	success = t.tree.InsertOrIgnore(entry.NewKV(key, value))
+	if success {
+		t.onInsert(key, value)
+	}
 	return
 }
 func (t *treeMap[K, V]) Get(key K) (value V, exists bool) {
+	if !t.inRange(key) {
+		return
+	}
 	e, exists := t.tree.Find(entry.Key[K, V](key))
 	value = e.Value
 	return
 }
 
 func (t *treeMap[K, V]) Delete(key K) (value V, exists bool) {
+	if !t.inRange(key) {
+		return
+	}
 	e, exists := t.tree.Delete(entry.Key[K, V](key))
 	value = e.Value
+	if exists {
+		t.onDelete(e.Key, value)
+	}
+	return
+}
+
+func (t *treeMap[K, V]) Compute(key K, f func(old V, exists bool) (value V, keep bool)) (value V, exists bool) {
+	t.checkRange(key)
+	var old entry.KV[K, V]
+	var existed bool
+	e, exists := t.tree.Compute(entry.Key[K, V](key), func(o entry.KV[K, V], exists bool) (entry.KV[K, V], bool) {
+		old, existed = o, exists
+		value, keep := f(o.Value, exists)
+		return entry.NewKV(key, value), keep
+	})
+	value = e.Value
+	switch {
+	case existed && exists:
+		t.onReplace(key, old.Value, value)
+	case exists:
+		t.onInsert(key, value)
+	case existed:
+		t.onDelete(old.Key, old.Value)
+	}
+	return
+}
+
+func (t *treeMap[K, V]) ComputeIfAbsent(key K, f func() V) (value V) {
+	value, _ = t.Compute(key, func(old V, exists bool) (V, bool) {
+		if exists {
+			return old, true
+		}
+		return f(), true
+	})
+	return
+}
+
+func (t *treeMap[K, V]) ComputeIfPresent(key K, f func(old V) (value V, keep bool)) (value V, exists bool) {
+	if !t.inRange(key) {
+		return
+	}
+	return t.Compute(key, func(old V, exists bool) (V, bool) {
+		if !exists {
+			return old, false
+		}
+		return f(old)
+	})
+}
+
+func (t *treeMap[K, V]) Merge(key K, value V, f func(old, value V) V) (res V) {
+	res, _ = t.Compute(key, func(old V, exists bool) (V, bool) {
+		if exists {
+			return f(old, value), true
+		}
+		return value, true
+	})
 	return
 }
 
 func (t *treeMap[K, V]) Len() int {
-	return t.tree.Size()
+	if !t.bounded() {
+		return t.tree.Size()
+	}
+	if n := t.countNotAbove() - t.countBelow(); n > 0 {
+		return n
+	}
+	return 0
 }
 
 func (t *treeMap[K, V]) Clear() {
-	t.tree.Clear()
+	if !t.bounded() {
+		t.tree.Clear()
+		t.onClear()
+		return
+	}
+	var keys []K
+	t.rangeEntries(func(e entry.KV[K, V]) bool {
+		keys = append(keys, e.Key)
+		return true
+	})
+	for _, key := range keys {
+		if e, exists := t.tree.Delete(entry.Key[K, V](key)); exists {
+			t.onDelete(e.Key, e.Value)
+		}
+	}
 }
 
-func (t *treeMap[K, V]) KeySet() func(yield func(K) bool) {
+func (t *treeMap[K, V]) KeySet() datastructure.Seq[K] {
 	return func(yield func(K) bool) {
-		t.tree.Range(func(e entry.KV[K, V]) bool {
+		t.rangeEntries(func(e entry.KV[K, V]) bool {
 			return yield(e.Key)
 		})
 	}
 }
 
-func (t *treeMap[K, V]) Items() func(yield func(K, V) bool) {
+func (t *treeMap[K, V]) Items() datastructure.Seq2[K, V] {
 	return func(yield func(K, V) bool) {
-		t.tree.Range(func(e entry.KV[K, V]) bool {
+		t.rangeEntries(func(e entry.KV[K, V]) bool {
 			return yield(e.Key, e.Value)
 		})
 	}
 }
 
-func NewMap[K compare.Ordered, V any]() TreeMap[K, V] {
-	return AsMap[K, V](avl.New[entry.KV[K, V]](entry.OrderedKeyLessCompareF[K, V]()))
+func (t *treeMap[K, V]) Keys() []K {
+	res := make([]K, 0, t.Len())
+	t.rangeEntries(func(e entry.KV[K, V]) bool {
+		res = append(res, e.Key)
+		return true
+	})
+	return res
 }
 
-func NewMapWithLess[K any, V any](less compare.Less[K]) TreeMap[K, V] {
+func (t *treeMap[K, V]) Values() []V {
+	res := make([]V, 0, t.Len())
+	t.rangeEntries(func(e entry.KV[K, V]) bool {
+		res = append(res, e.Value)
+		return true
+	})
+	return res
+}
+
+func (t *treeMap[K, V]) FirstEntry() (e entry.KV[K, V], exists bool) {
+	switch {
+	case t.lo.IsUnbounded():
+		e, exists = t.tree.Min()
+	case t.lo.IsIncluded():
+		e, exists = t.tree.FindOrNext(entry.Key[K, V](t.lo.Key()))
+	default:
+		e, exists = t.tree.Next(entry.Key[K, V](t.lo.Key()))
+	}
+	return t.within(e, exists)
+}
+
+func (t *treeMap[K, V]) LastEntry() (e entry.KV[K, V], exists bool) {
+	switch {
+	case t.hi.IsUnbounded():
+		e, exists = t.tree.Max()
+	case t.hi.IsIncluded():
+		e, exists = t.tree.FindOrPrev(entry.Key[K, V](t.hi.Key()))
+	default:
+		e, exists = t.tree.Prev(entry.Key[K, V](t.hi.Key()))
+	}
+	return t.within(e, exists)
+}
+
+func (t *treeMap[K, V]) FloorEntry(key K) (e entry.KV[K, V], exists bool) {
+	if t.tooHigh(key) {
+		return t.LastEntry()
+	}
+	return t.within(t.tree.FindOrPrev(entry.Key[K, V](key)))
+}
+
+func (t *treeMap[K, V]) CeilingEntry(key K) (e entry.KV[K, V], exists bool) {
+	if t.tooLow(key) {
+		return t.FirstEntry()
+	}
+	return t.within(t.tree.FindOrNext(entry.Key[K, V](key)))
+}
+
+func (t *treeMap[K, V]) LowerEntry(key K) (e entry.KV[K, V], exists bool) {
+	if t.tooHigh(key) {
+		return t.LastEntry()
+	}
+	return t.within(t.tree.Prev(entry.Key[K, V](key)))
+}
+
+func (t *treeMap[K, V]) HigherEntry(key K) (e entry.KV[K, V], exists bool) {
+	if t.tooLow(key) {
+		return t.FirstEntry()
+	}
+	return t.within(t.tree.Next(entry.Key[K, V](key)))
+}
+
+func (t *treeMap[K, V]) PollFirst() (e entry.KV[K, V], exists bool) {
+	if e, exists = t.FirstEntry(); exists {
+		t.tree.Delete(e)
+		t.onDelete(e.Key, e.Value)
+	}
+	return
+}
+
+func (t *treeMap[K, V]) PollLast() (e entry.KV[K, V], exists bool) {
+	if e, exists = t.LastEntry(); exists {
+		t.tree.Delete(e)
+		t.onDelete(e.Key, e.Value)
+	}
+	return
+}
+
+func (t *treeMap[K, V]) At(i int) (key K, value V, exists bool) {
+	if i < 0 || (t.bounded() && i >= t.Len()) {
+		return
+	}
+	e, exists := t.tree.RankNth(t.countBelow() + i + 1)
+	key, value = e.Key, e.Value
+	return
+}
+
+func (t *treeMap[K, V]) IndexOf(key K) int {
+	probe := entry.Key[K, V](key)
+	if !t.inRange(key) || !t.tree.Exists(probe) {
+		return -1
+	}
+	return t.tree.Rank(probe) - 1 - t.countBelow()
+}
+
+func (t *treeMap[K, V]) RangeByIndex(from, to int) datastructure.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		lo, hi := from, to
+		if lo < 0 {
+			lo = 0
+		}
+		if n := t.Len(); hi > n {
+			hi = n
+		}
+		if lo >= hi {
+			return
+		}
+		start, _ := t.tree.RankNth(t.countBelow() + lo + 1)
+		n := hi - lo
+		t.tree.RangeS(start, func(e entry.KV[K, V]) bool {
+			n--
+			return yield(e.Key, e.Value) && n > 0
+		})
+	}
+}
+
+// within drops the entry that is out of range of the view.
+func (t *treeMap[K, V]) within(e entry.KV[K, V], exists bool) (entry.KV[K, V], bool) {
+	if exists && !t.inRange(e.Key) {
+		var zero entry.KV[K, V]
+		return zero, false
+	}
+	return e, exists
+}
+
+func NewMap[K compare.Ordered, V any](opts ...OptionFunc[K, V]) TreeMap[K, V] {
+	return AsMap[K, V](avl.New[entry.KV[K, V]](entry.OrderedKeyLessCompareF[K, V]()), opts...)
+}
+
+func NewMapWithLess[K any, V any](less compare.Less[K], opts ...OptionFunc[K, V]) TreeMap[K, V] {
 	return AsMap[K, V](
 		avl.New(
 			entry.KeyCompareWrapper[K, V](compare.LessF[K](less)),
 		),
+		opts...,
 	)
 }
 
-func NewMapWithCompare[K any, V any](keyCompare compare.ICompare[K]) TreeMap[K, V] {
+func NewMapWithCompare[K any, V any](keyCompare compare.ICompare[K], opts ...OptionFunc[K, V]) TreeMap[K, V] {
 	return AsMap[K, V](
 		avl.New(
 			entry.KeyCompareWrapper[K, V](keyCompare),
 		),
+		opts...,
 	)
 }
 
 // AsMap Create a TreeMap base on the BinarySearchTree
-func AsMap[K any, V any](tree bst.BinarySearchTree[entry.KV[K, V]]) TreeMap[K, V] {
+func AsMap[K any, V any](tree bst.BinarySearchTree[entry.KV[K, V]], opts ...OptionFunc[K, V]) TreeMap[K, V] {
 	if tree == nil {
 		panic("AsMap: tree is nil")
 	}
+	var opt = getOption(opts)
 	m := &treeMap[K, V]{
-		tree: tree,
+		tree:     tree,
+		keyCodec: opt.keyCodec,
+		hooks:    &hookList[K, V]{},
+	}
+	if m.keyCodec == nil {
+		m.keyCodec = defaultKeyCodec[K]()
 	}
 	return m
 }
@@ -2,7 +2,13 @@ package treemap
 
 import (
 	"fmt"
+	"math"
 	"testing"
+
+	"github.com/Sora233/datastructure/bst"
+	"github.com/Sora233/datastructure/bst/avl"
+	"github.com/Sora233/datastructure/entry"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestNewMap(t *testing.T) {
@@ -40,3 +46,226 @@ func TestNewMapWithLess(t *testing.T) {
 		return true
 	})
 }
+
+func TestNewMapNaN(t *testing.T) {
+	m := NewMap[float64, int]()
+	m.Put(1, 1)
+	m.Put(math.NaN(), 2)
+	m.Put(math.Inf(-1), 3)
+	m.Put(math.NaN(), 4)
+	m.Put(math.Copysign(0, -1), 5)
+	m.Put(0, 6)
+	assert.Equal(t, 4, m.Len())
+	v, ok := m.Get(math.NaN())
+	assert.True(t, ok)
+	assert.Equal(t, 4, v)
+	v, ok = m.Get(0)
+	assert.True(t, ok)
+	assert.Equal(t, 6, v)
+	var values []int
+	m.Items()(func(_ float64, v int) bool {
+		values = append(values, v)
+		return true
+	})
+	assert.Equal(t, []int{4, 3, 6, 1}, values)
+	_, ok = m.Delete(math.NaN())
+	assert.True(t, ok)
+	assert.Equal(t, 3, m.Len())
+}
+
+func TestMapNavigation(t *testing.T) {
+	m := NewMap[int, string]()
+	_, ok := m.FirstEntry()
+	assert.False(t, ok)
+	_, ok = m.PollLast()
+	assert.False(t, ok)
+	for _, k := range []int{10, 30, 20} {
+		m.Put(k, fmt.Sprint(k))
+	}
+
+	e, ok := m.FirstEntry()
+	assert.True(t, ok)
+	assert.Equal(t, 10, e.Key)
+	assert.Equal(t, "10", e.Value)
+	e, _ = m.LastEntry()
+	assert.Equal(t, 30, e.Key)
+
+	e, _ = m.FloorEntry(20)
+	assert.Equal(t, 20, e.Key)
+	e, _ = m.FloorEntry(25)
+	assert.Equal(t, 20, e.Key)
+	_, ok = m.FloorEntry(5)
+	assert.False(t, ok)
+	e, _ = m.CeilingEntry(20)
+	assert.Equal(t, 20, e.Key)
+	e, _ = m.CeilingEntry(15)
+	assert.Equal(t, 20, e.Key)
+	_, ok = m.CeilingEntry(35)
+	assert.False(t, ok)
+
+	e, _ = m.LowerEntry(20)
+	assert.Equal(t, 10, e.Key)
+	_, ok = m.LowerEntry(10)
+	assert.False(t, ok)
+	e, _ = m.HigherEntry(20)
+	assert.Equal(t, 30, e.Key)
+	_, ok = m.HigherEntry(30)
+	assert.False(t, ok)
+
+	e, ok = m.PollFirst()
+	assert.True(t, ok)
+	assert.Equal(t, 10, e.Key)
+	e, ok = m.PollLast()
+	assert.True(t, ok)
+	assert.Equal(t, "30", e.Value)
+	assert.Equal(t, 1, m.Len())
+	e, _ = m.FirstEntry()
+	assert.Equal(t, 20, e.Key)
+}
+
+func TestMapCompute(t *testing.T) {
+	m := NewMap[string, int]()
+	for _, w := range []string{"a", "b", "a", "c", "a"} {
+		m.Merge(w, 1, func(old, value int) int { return old + value })
+	}
+	v, _ := m.Get("a")
+	assert.Equal(t, 3, v)
+	assert.Equal(t, 3, m.Len())
+
+	v, ok := m.Compute("b", func(old int, exists bool) (int, bool) {
+		assert.True(t, exists)
+		return old * 10, true
+	})
+	assert.True(t, ok)
+	assert.Equal(t, 10, v)
+	_, ok = m.Compute("c", func(old int, exists bool) (int, bool) { return 0, false })
+	assert.False(t, ok)
+	_, ok = m.Get("c")
+	assert.False(t, ok)
+	_, ok = m.Compute("d", func(old int, exists bool) (int, bool) {
+		assert.False(t, exists)
+		return 0, false
+	})
+	assert.False(t, ok)
+	assert.Equal(t, 2, m.Len())
+
+	assert.Equal(t, 3, m.ComputeIfAbsent("a", func() int { panic("called on existing key") }))
+	assert.Equal(t, 7, m.ComputeIfAbsent("e", func() int { return 7 }))
+
+	_, ok = m.ComputeIfPresent("x", func(int) (int, bool) { panic("called on absent key") })
+	assert.False(t, ok)
+	_, ok = m.Get("x")
+	assert.False(t, ok)
+	v, ok = m.ComputeIfPresent("e", func(old int) (int, bool) { return old + 1, true })
+	assert.True(t, ok)
+	assert.Equal(t, 8, v)
+	_, ok = m.ComputeIfPresent("e", func(old int) (int, bool) { return old, false })
+	assert.False(t, ok)
+	assert.Equal(t, []string{"a", "b"}, collectKeys(m))
+
+	head := m.HeadMap("b")
+	assert.Panics(t, func() { head.Merge("b", 1, func(old, value int) int { return old + value }) })
+	_, ok = head.ComputeIfPresent("b", func(old int) (int, bool) { panic("called out of range") })
+	assert.False(t, ok)
+}
+
+func TestMapPosition(t *testing.T) {
+	m := NewMap[int, string]()
+	for k := 0; k < 10; k++ {
+		m.Put(k*10, fmt.Sprint(k))
+	}
+	k, v, ok := m.At(3)
+	assert.True(t, ok)
+	assert.Equal(t, 30, k)
+	assert.Equal(t, "3", v)
+	_, _, ok = m.At(10)
+	assert.False(t, ok)
+	_, _, ok = m.At(-1)
+	assert.False(t, ok)
+	assert.Equal(t, 9, m.IndexOf(90))
+	assert.Equal(t, -1, m.IndexOf(15))
+
+	var keys []int
+	m.RangeByIndex(2, 5)(func(k int, v string) bool {
+		keys = append(keys, k)
+		return true
+	})
+	assert.Equal(t, []int{20, 30, 40}, keys)
+	keys = nil
+	m.RangeByIndex(-3, 100)(func(k int, v string) bool {
+		keys = append(keys, k)
+		return len(keys) < 2
+	})
+	assert.Equal(t, []int{0, 10}, keys)
+	m.RangeByIndex(5, 5)(func(int, string) bool { panic("empty range") })
+
+	sub := m.SubMap(20, 60, ExcludeBoth)
+	k, _, ok = sub.At(0)
+	assert.True(t, ok)
+	assert.Equal(t, 30, k)
+	_, _, ok = sub.At(3)
+	assert.False(t, ok)
+	assert.Equal(t, 2, sub.IndexOf(50))
+	assert.Equal(t, -1, sub.IndexOf(60))
+	keys = nil
+	sub.RangeByIndex(1, 10)(func(k int, v string) bool {
+		keys = append(keys, k)
+		return true
+	})
+	assert.Equal(t, []int{40, 50}, keys)
+
+	// the iterator clamps the indexes on every run
+	small := NewMap[int, string]()
+	small.Put(0, "0")
+	seq := small.RangeByIndex(0, 100)
+	keys = nil
+	seq(func(k int, v string) bool {
+		keys = append(keys, k)
+		return true
+	})
+	assert.Equal(t, []int{0}, keys)
+	small.Put(1, "1")
+	keys = nil
+	seq(func(k int, v string) bool {
+		keys = append(keys, k)
+		return true
+	})
+	assert.Equal(t, []int{0, 1}, keys)
+}
+
+func TestMapModification(t *testing.T) {
+	m := NewMap[int, int]()
+	for k := 0; k < 10; k++ {
+		m.Put(k, k)
+	}
+	m.KeySet()(func(key int) bool {
+		m.Put(key, key*2)
+		return true
+	})
+	assert.Equal(t, 10, m.Len())
+	assert.PanicsWithValue(t, bst.ErrModifiedDuringIteration, func() {
+		m.KeySet()(func(key int) bool {
+			m.Put(key+100, key)
+			return true
+		})
+	})
+	assert.PanicsWithValue(t, bst.ErrModifiedDuringIteration, func() {
+		m.TailMap(5).Items()(func(key int, value int) bool {
+			m.Delete(key)
+			return true
+		})
+	})
+
+	m = AsMap[int, int](avl.New(entry.OrderedKeyLessCompareF[int, int](), avl.WithSafeIteration[entry.KV[int, int]]()))
+	for k := 0; k < 10; k++ {
+		m.Put(k, k)
+	}
+	var keys []int
+	m.KeySet()(func(key int) bool {
+		keys = append(keys, key)
+		m.Delete(key + 1)
+		return true
+	})
+	assert.Equal(t, []int{0, 2, 4, 6, 8}, keys)
+	assert.Equal(t, 5, m.Len())
+}
@@ -0,0 +1,224 @@
+package treemap
+
+import (
+	"github.com/Sora233/datastructure"
+	"github.com/Sora233/datastructure/allocator"
+	"github.com/Sora233/datastructure/bst"
+	"github.com/Sora233/datastructure/bst/avl"
+	"github.com/Sora233/datastructure/compare"
+	"github.com/Sora233/datastructure/entry"
+)
+
+// MultiMap is a sorted map that associates each key with multiple values.
+// The values of a key are kept either in insertion order or in value order, depending on the constructor.
+// It is not safe for concurrent use.
+type MultiMap[K any, V any] interface {
+	// Put appends value to the values of key.
+	Put(key K, value V)
+	// Get return the values of key, or nil if key does not exist.
+	Get(key K) []V
+	// DeleteValue deletes one occurrence of value from the values of key.
+	// The key is deleted with its last value.
+	// return true if the value exists and is deleted.
+	DeleteValue(key K, value V) (success bool)
+	// DeleteAll deletes key and return all of its values.
+	DeleteAll(key K) (values []V)
+	// KeyCount return the number of distinct keys.
+	KeyCount() int
+	// ValueCount return the number of values of all keys.
+	ValueCount() int
+	Clear()
+	// KeySet iterate over the distinct keys in ascending order.
+	KeySet() datastructure.Seq[K]
+	// Items iterate over every (key, value) pair in ascending order of keys,
+	// the values of a key are iterated in the order that Get returns.
+	Items() datastructure.Seq2[K, V]
+}
+
+// bucket is the container of the values of a key.
+type bucket[V any] interface {
+	add(value V)
+	delete(value V) bool
+	len() int
+	rangeValues(f func(V) bool) bool
+}
+
+// sliceBucket keeps the values in insertion order.
+type sliceBucket[V any] struct {
+	data []V
+	eq   func(a, b V) bool
+}
+
+func (s *sliceBucket[V]) add(value V) {
+	s.data = append(s.data, value)
+}
+
+func (s *sliceBucket[V]) delete(value V) bool {
+	for i := range s.data {
+		if s.eq(s.data[i], value) {
+			s.data = append(s.data[:i], s.data[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (s *sliceBucket[V]) len() int {
+	return len(s.data)
+}
+
+func (s *sliceBucket[V]) rangeValues(f func(V) bool) bool {
+	for _, v := range s.data {
+		if !f(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// treeBucket keeps the values in value order.
+type treeBucket[V any] struct {
+	tree *avl.AVL[V]
+}
+
+func (s *treeBucket[V]) add(value V) {
+	s.tree.Insert(value)
+}
+
+func (s *treeBucket[V]) delete(value V) bool {
+	_, success := s.tree.Delete(value)
+	return success
+}
+
+func (s *treeBucket[V]) len() int {
+	return s.tree.Size()
+}
+
+func (s *treeBucket[V]) rangeValues(f func(V) bool) bool {
+	res := true
+	s.tree.RangeCount(func(value V, count int) bool {
+		for i := 0; i < count; i++ {
+			if !f(value) {
+				res = false
+				return false
+			}
+		}
+		return true
+	})
+	return res
+}
+
+type multiMap[K any, V any] struct {
+	tree       bst.BinarySearchTree[entry.KV[K, bucket[V]]]
+	newBucket  func() bucket[V]
+	valueCount int
+}
+
+func (t *multiMap[K, V]) Put(key K, value V) {
+	e, exists := t.tree.Find(entry.Key[K, bucket[V]](key))
+	if !exists {
+		e = entry.NewKV(key, t.newBucket())
+		t.tree.Insert(e)
+	}
+	e.Value.add(value)
+	t.valueCount++
+}
+
+func (t *multiMap[K, V]) Get(key K) []V {
+	e, exists := t.tree.Find(entry.Key[K, bucket[V]](key))
+	if !exists {
+		return nil
+	}
+	res := make([]V, 0, e.Value.len())
+	e.Value.rangeValues(func(v V) bool {
+		res = append(res, v)
+		return true
+	})
+	return res
+}
+
+func (t *multiMap[K, V]) DeleteValue(key K, value V) (success bool) {
+	t.tree.DeleteIf(entry.Key[K, bucket[V]](key), func(e entry.KV[K, bucket[V]]) bool {
+		success = e.Value.delete(value)
+		return e.Value.len() == 0
+	})
+	if success {
+		t.valueCount--
+	}
+	return
+}
+
+func (t *multiMap[K, V]) DeleteAll(key K) (values []V) {
+	values = t.Get(key)
+	if _, exists := t.tree.Delete(entry.Key[K, bucket[V]](key)); exists {
+		t.valueCount -= len(values)
+	}
+	return
+}
+
+func (t *multiMap[K, V]) KeyCount() int {
+	return t.tree.Size()
+}
+
+func (t *multiMap[K, V]) ValueCount() int {
+	return t.valueCount
+}
+
+func (t *multiMap[K, V]) Clear() {
+	t.tree.Clear()
+	t.valueCount = 0
+}
+
+func (t *multiMap[K, V]) KeySet() datastructure.Seq[K] {
+	return func(yield func(K) bool) {
+		t.tree.Range(func(e entry.KV[K, bucket[V]]) bool {
+			return yield(e.Key)
+		})
+	}
+}
+
+func (t *multiMap[K, V]) Items() datastructure.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		t.tree.Range(func(e entry.KV[K, bucket[V]]) bool {
+			return e.Value.rangeValues(func(v V) bool {
+				return yield(e.Key, v)
+			})
+		})
+	}
+}
+
+func newMultiMap[K any, V any](keyCompare compare.ICompare[K], newBucket func() bucket[V]) MultiMap[K, V] {
+	return &multiMap[K, V]{
+		tree:      avl.New(entry.KeyCompareWrapper[K, bucket[V]](keyCompare)),
+		newBucket: newBucket,
+	}
+}
+
+// NewMultiMap create a MultiMap that keeps the values of each key in insertion order.
+func NewMultiMap[K compare.Ordered, V comparable]() MultiMap[K, V] {
+	return NewMultiMapWithCompare[K, V](compare.OrderedLessCompareF[K]())
+}
+
+// NewMultiMapWithCompare create a MultiMap that keeps the values of each key in insertion order.
+func NewMultiMapWithCompare[K any, V comparable](keyCompare compare.ICompare[K]) MultiMap[K, V] {
+	eq := func(a, b V) bool {
+		return a == b
+	}
+	return newMultiMap[K, V](keyCompare, func() bucket[V] {
+		return &sliceBucket[V]{eq: eq}
+	})
+}
+
+// NewSortedMultiMap create a MultiMap that keeps the values of each key in ascending order.
+func NewSortedMultiMap[K compare.Ordered, V compare.Ordered]() MultiMap[K, V] {
+	return NewSortedMultiMapWithCompare[K, V](compare.OrderedLessCompareF[K](), compare.OrderedLessCompareF[V]())
+}
+
+// NewSortedMultiMapWithCompare create a MultiMap that keeps the values of each key in the order of valueCompare.
+// The values that valueCompare considers EQ are treated as the same value,
+// only the first of them is stored and the others are counted as its occurrences.
+func NewSortedMultiMapWithCompare[K any, V any](keyCompare compare.ICompare[K], valueCompare compare.ICompare[V]) MultiMap[K, V] {
+	return newMultiMap[K, V](keyCompare, func() bucket[V] {
+		return &treeBucket[V]{tree: avl.New(valueCompare, avl.WithMultiset[V](), avl.WithAllocator[V](allocator.NewSimpleAllocator[avl.Node[V]]()))}
+	})
+}
@@ -0,0 +1,71 @@
+package treemap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiMap(t *testing.T) {
+	m := NewMultiMap[int, string]()
+	m.Put(2, "b")
+	m.Put(1, "z")
+	m.Put(2, "a")
+	m.Put(2, "b")
+	assert.Equal(t, 2, m.KeyCount())
+	assert.Equal(t, 4, m.ValueCount())
+	assert.Equal(t, []string{"b", "a", "b"}, m.Get(2))
+	assert.Nil(t, m.Get(3))
+
+	assert.True(t, m.DeleteValue(2, "b"))
+	assert.False(t, m.DeleteValue(2, "x"))
+	assert.False(t, m.DeleteValue(3, "x"))
+	assert.Equal(t, []string{"a", "b"}, m.Get(2))
+	assert.Equal(t, 3, m.ValueCount())
+
+	var keys []int
+	var values []string
+	m.Items()(func(k int, v string) bool {
+		keys = append(keys, k)
+		values = append(values, v)
+		return true
+	})
+	assert.Equal(t, []int{1, 2, 2}, keys)
+	assert.Equal(t, []string{"z", "a", "b"}, values)
+
+	assert.True(t, m.DeleteValue(1, "z"))
+	assert.Equal(t, 1, m.KeyCount())
+	assert.Equal(t, []string{"a", "b"}, m.DeleteAll(2))
+	assert.Nil(t, m.DeleteAll(2))
+	assert.Equal(t, 0, m.KeyCount())
+	assert.Equal(t, 0, m.ValueCount())
+}
+
+func TestSortedMultiMap(t *testing.T) {
+	m := NewSortedMultiMap[int, string]()
+	m.Put(1, "c")
+	m.Put(1, "a")
+	m.Put(1, "c")
+	m.Put(0, "x")
+	assert.Equal(t, []string{"a", "c", "c"}, m.Get(1))
+	assert.True(t, m.DeleteValue(1, "c"))
+	assert.Equal(t, []string{"a", "c"}, m.Get(1))
+	assert.Equal(t, 3, m.ValueCount())
+
+	var keys []int
+	m.KeySet()(func(k int) bool {
+		keys = append(keys, k)
+		return true
+	})
+	assert.Equal(t, []int{0, 1}, keys)
+
+	var n int
+	m.Items()(func(int, string) bool {
+		n++
+		return n < 2
+	})
+	assert.Equal(t, 2, n)
+	m.Clear()
+	assert.Equal(t, 0, m.ValueCount())
+	assert.Equal(t, 0, m.KeyCount())
+}
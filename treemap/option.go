@@ -0,0 +1,22 @@
+package treemap
+
+type option[K any, V any] struct {
+	keyCodec KeyCodec[K]
+}
+
+type OptionFunc[K any, V any] func(*option[K, V])
+
+// WithKeyCodec set the codec used to convert keys to and from JSON object keys
+func WithKeyCodec[K any, V any](codec KeyCodec[K]) OptionFunc[K, V] {
+	return func(o *option[K, V]) {
+		o.keyCodec = codec
+	}
+}
+
+func getOption[K any, V any](opts []OptionFunc[K, V]) *option[K, V] {
+	var opt = new(option[K, V])
+	for _, o := range opts {
+		o(opt)
+	}
+	return opt
+}
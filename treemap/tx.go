@@ -0,0 +1,123 @@
+package treemap
+
+import (
+	"github.com/Sora233/datastructure/bst"
+	"github.com/Sora233/datastructure/bst/avl"
+	"github.com/Sora233/datastructure/compare"
+	"github.com/Sora233/datastructure/entry"
+)
+
+// Tx is a batch of writes to a TreeMap that is applied all at once by Commit, or discarded by Rollback.
+// The writes are staged in the transaction and are visible to its Get only.
+// A Tx can not be used after Commit or Rollback.
+type Tx[K any, V any] interface {
+	// Put stages key with value.
+	Put(key K, value V)
+	// Delete stages the deletion of key.
+	Delete(key K)
+	// Get return the value of key with the staged writes applied.
+	Get(key K) (value V, exists bool)
+	// Commit applies the staged writes to the map in ascending order of keys.
+	// If a write panics, for example in a hook, the applied writes are undone and the panic is propagated.
+	Commit()
+	// Rollback discards the staged writes.
+	Rollback()
+}
+
+// op is a staged write.
+type op[V any] struct {
+	value   V
+	deleted bool
+}
+
+type tx[K any, V any] struct {
+	m      *treeMap[K, V]
+	staged bst.BinarySearchTree[entry.KV[K, op[V]]]
+	done   bool
+}
+
+func (t *treeMap[K, V]) Begin() Tx[K, V] {
+	keys := keyCompare[K, V]{t.tree.Comparator()}
+	return &tx[K, V]{
+		m: t,
+		staged: avl.New(compare.WithFunc(func(a, b entry.KV[K, op[V]]) compare.Result {
+			return keys.Compare(a.Key, b.Key)
+		})),
+	}
+}
+
+func (t *tx[K, V]) Put(key K, value V) {
+	t.check()
+	t.m.checkRange(key)
+	t.staged.Insert(entry.NewKV(key, op[V]{value: value}))
+}
+
+func (t *tx[K, V]) Delete(key K) {
+	t.check()
+	t.m.checkRange(key)
+	t.staged.Insert(entry.NewKV(key, op[V]{deleted: true}))
+}
+
+func (t *tx[K, V]) Get(key K) (value V, exists bool) {
+	t.check()
+	if e, staged := t.staged.Find(entry.Key[K, op[V]](key)); staged {
+		if e.Value.deleted {
+			return
+		}
+		return e.Value.value, true
+	}
+	return t.m.Get(key)
+}
+
+func (t *tx[K, V]) Commit() {
+	t.check()
+	t.done = true
+	// undo records the state of each applied key before the write
+	var undo []entry.KV[K, op[V]]
+	committed := false
+	defer func() {
+		if committed {
+			return
+		}
+		for i := len(undo) - 1; i >= 0; i-- {
+			t.undo(undo[i])
+		}
+	}()
+	t.staged.Range(func(e entry.KV[K, op[V]]) bool {
+		old, exists := t.m.Get(e.Key)
+		undo = append(undo, entry.NewKV(e.Key, op[V]{value: old, deleted: !exists}))
+		if e.Value.deleted {
+			t.m.Delete(e.Key)
+		} else {
+			t.m.Put(e.Key, e.Value.value)
+		}
+		return true
+	})
+	committed = true
+	t.staged.Clear()
+}
+
+// undo restores the state of a key before the write.
+// A panic of the hooks is dropped, so that every undo step runs and the original panic is propagated.
+func (t *tx[K, V]) undo(e entry.KV[K, op[V]]) {
+	defer func() {
+		recover()
+	}()
+	if e.Value.deleted {
+		t.m.Delete(e.Key)
+	} else {
+		t.m.Put(e.Key, e.Value.value)
+	}
+}
+
+func (t *tx[K, V]) Rollback() {
+	t.check()
+	t.done = true
+	t.staged.Clear()
+}
+
+func (t *tx[K, V]) check() {
+	if t.done {
+		panic("treemap: transaction is already committed or rolled back")
+	}
+}
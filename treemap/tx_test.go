@@ -0,0 +1,96 @@
+package treemap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTx(t *testing.T) {
+	m := NewMap[string, int]()
+	m.Put("a", 1)
+	m.Put("b", 2)
+
+	tx := m.Begin()
+	tx.Put("c", 3)
+	tx.Put("a", 10)
+	tx.Delete("b")
+	tx.Delete("x")
+	v, ok := tx.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 10, v)
+	_, ok = tx.Get("b")
+	assert.False(t, ok)
+	v, _ = tx.Get("c")
+	assert.Equal(t, 3, v)
+	v, _ = m.Get("a")
+	assert.Equal(t, 1, v, "staged writes are invisible to the map")
+	_, ok = m.Get("c")
+	assert.False(t, ok)
+
+	tx.Commit()
+	assert.Equal(t, []string{"a", "c"}, m.Keys())
+	assert.Equal(t, []int{10, 3}, m.Values())
+	assert.Panics(t, func() { tx.Put("d", 4) })
+	assert.Panics(t, tx.Commit)
+
+	tx = m.Begin()
+	tx.Put("d", 4)
+	tx.Delete("a")
+	tx.Rollback()
+	assert.Equal(t, []string{"a", "c"}, m.Keys())
+	assert.Panics(t, tx.Rollback)
+}
+
+func TestTxUndo(t *testing.T) {
+	m := NewMap[int, string]()
+	m.Put(1, "a")
+	m.Put(2, "b")
+	m.Put(3, "c")
+	m.AddHooks(Hooks[int, string]{
+		OnInsert: func(key int, value string) {
+			if value == "boom" {
+				panic("boom")
+			}
+		},
+	})
+
+	tx := m.Begin()
+	tx.Put(1, "x")
+	tx.Delete(2)
+	tx.Put(4, "y")
+	tx.Put(5, "boom")
+	assert.PanicsWithValue(t, "boom", tx.Commit)
+	assert.Equal(t, []int{1, 2, 3}, m.Keys())
+	assert.Equal(t, []string{"a", "b", "c"}, m.Values())
+
+	head := m.HeadMap(3)
+	tx = head.Begin()
+	assert.Panics(t, func() { tx.Put(3, "z") })
+	assert.Panics(t, func() { tx.Delete(3) })
+	tx.Put(0, "z")
+	tx.Commit()
+	assert.Equal(t, []int{0, 1, 2, 3}, m.Keys())
+}
+
+func TestTxUndoHookPanic(t *testing.T) {
+	m := NewMap[int, string]()
+	m.Put(1, "a")
+	m.Put(2, "b")
+	// the hook panics on both the forward write and the undo write of key 2
+	m.AddHooks(Hooks[int, string]{
+		OnReplace: func(key int, old, value string) {
+			if key == 2 {
+				panic("boom")
+			}
+		},
+	})
+
+	tx := m.Begin()
+	tx.Put(0, "z")
+	tx.Put(1, "x")
+	tx.Put(2, "y")
+	assert.PanicsWithValue(t, "boom", tx.Commit)
+	assert.Equal(t, []int{1, 2}, m.Keys())
+	assert.Equal(t, []string{"a", "b"}, m.Values())
+}
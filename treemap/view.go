@@ -0,0 +1,157 @@
+package treemap
+
+import (
+	"github.com/Sora233/datastructure"
+	"github.com/Sora233/datastructure/bst"
+	"github.com/Sora233/datastructure/compare"
+	"github.com/Sora233/datastructure/entry"
+)
+
+// Inclusivity determines whether the endpoints of SubMap are included in the view.
+type Inclusivity uint8
+
+const (
+	// ExcludeBoth excludes both from and to.
+	ExcludeBoth Inclusivity = 0
+	// IncludeFrom includes from.
+	IncludeFrom Inclusivity = 1 << 0
+	// IncludeTo includes to.
+	IncludeTo Inclusivity = 1 << 1
+	// IncludeBoth includes both from and to.
+	IncludeBoth = IncludeFrom | IncludeTo
+)
+
+func (t *treeMap[K, V]) SubMap(from, to K, inclusivity Inclusivity) TreeMap[K, V] {
+	lo, hi := bst.Excluded(from), bst.Excluded(to)
+	if inclusivity&IncludeFrom != 0 {
+		lo = bst.Included(from)
+	}
+	if inclusivity&IncludeTo != 0 {
+		hi = bst.Included(to)
+	}
+	return t.view(t.withLo(lo), t.withHi(hi))
+}
+
+func (t *treeMap[K, V]) HeadMap(to K) TreeMap[K, V] {
+	return t.view(t.lo, t.withHi(bst.Excluded(to)))
+}
+
+func (t *treeMap[K, V]) TailMap(from K) TreeMap[K, V] {
+	return t.view(t.withLo(bst.Included(from)), t.hi)
+}
+
+func (t *treeMap[K, V]) Range(lo, hi bst.Bound[K]) datastructure.Seq2[K, V] {
+	return t.view(t.withLo(lo), t.withHi(hi)).Items()
+}
+
+func (t *treeMap[K, V]) view(lo, hi bst.Bound[K]) TreeMap[K, V] {
+	return &treeMap[K, V]{
+		tree:     t.tree,
+		keyCodec: t.keyCodec,
+		lo:       lo,
+		hi:       hi,
+		hooks:    t.hooks,
+	}
+}
+
+// withLo return the lower bound of the intersection of the view and the range that starts from lo.
+func (t *treeMap[K, V]) withLo(lo bst.Bound[K]) bst.Bound[K] {
+	if lo.IsUnbounded() {
+		return t.lo
+	}
+	if t.lo.IsUnbounded() {
+		return lo
+	}
+	r := t.compare(lo.Key(), t.lo.Key())
+	if r.GT() || (r.EQ() && lo.IsExcluded()) {
+		return lo
+	}
+	return t.lo
+}
+
+// withHi return the upper bound of the intersection of the view and the range that ends at hi.
+func (t *treeMap[K, V]) withHi(hi bst.Bound[K]) bst.Bound[K] {
+	if hi.IsUnbounded() {
+		return t.hi
+	}
+	if t.hi.IsUnbounded() {
+		return hi
+	}
+	r := t.compare(hi.Key(), t.hi.Key())
+	if r.LT() || (r.EQ() && hi.IsExcluded()) {
+		return hi
+	}
+	return t.hi
+}
+
+func (t *treeMap[K, V]) bounded() bool {
+	return !t.lo.IsUnbounded() || !t.hi.IsUnbounded()
+}
+
+// keyCompare compares the keys with the comparator of the entries.
+type keyCompare[K any, V any] struct {
+	cmp compare.ICompare[entry.KV[K, V]]
+}
+
+func (c keyCompare[K, V]) Compare(a, b K) compare.Result {
+	return c.cmp.Compare(entry.Key[K, V](a), entry.Key[K, V](b))
+}
+
+func (t *treeMap[K, V]) compare(a, b K) compare.Result {
+	return keyCompare[K, V]{t.tree.Comparator()}.Compare(a, b)
+}
+
+// tooLow return true if key is below the lower bound of the view.
+func (t *treeMap[K, V]) tooLow(key K) bool {
+	return !t.lo.IsLowerOf(keyCompare[K, V]{t.tree.Comparator()}, key)
+}
+
+// tooHigh return true if key is above the upper bound of the view.
+func (t *treeMap[K, V]) tooHigh(key K) bool {
+	return !t.hi.IsUpperOf(keyCompare[K, V]{t.tree.Comparator()}, key)
+}
+
+func (t *treeMap[K, V]) inRange(key K) bool {
+	return !t.tooLow(key) && !t.tooHigh(key)
+}
+
+func (t *treeMap[K, V]) checkRange(key K) {
+	if !t.inRange(key) {
+		panic("treemap: key out of range of the view")
+	}
+}
+
+// rangeEntries iterate over the entries in the view in ascending order.
+func (t *treeMap[K, V]) rangeEntries(f func(e entry.KV[K, V]) bool) {
+	if !t.bounded() {
+		t.tree.Range(f)
+		return
+	}
+	t.tree.RangeBounds(bst.MapBound(t.lo, entry.Key[K, V]), bst.MapBound(t.hi, entry.Key[K, V]), f)
+}
+
+// countBelow return the number of entries in the tree below the lower bound of the view.
+func (t *treeMap[K, V]) countBelow() int {
+	if t.lo.IsUnbounded() {
+		return 0
+	}
+	probe := entry.Key[K, V](t.lo.Key())
+	n := t.tree.Rank(probe) - 1
+	if t.lo.IsExcluded() && t.tree.Exists(probe) {
+		n++
+	}
+	return n
+}
+
+// countNotAbove return the number of entries in the tree that are not above the upper bound of the view.
+func (t *treeMap[K, V]) countNotAbove() int {
+	if t.hi.IsUnbounded() {
+		return t.tree.Size()
+	}
+	probe := entry.Key[K, V](t.hi.Key())
+	n := t.tree.Rank(probe) - 1
+	if t.hi.IsIncluded() && t.tree.Exists(probe) {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,165 @@
+package treemap
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/Sora233/datastructure/bst"
+	"github.com/stretchr/testify/assert"
+)
+
+func collectKeys[K any, V any](m TreeMap[K, V]) []K {
+	var keys []K
+	m.KeySet()(func(k K) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return keys
+}
+
+func TestSubMap(t *testing.T) {
+	m := NewMap[int, int]()
+	for k := 0; k < 20; k += 2 {
+		m.Put(k, k*10)
+	}
+	r := rand.New(rand.NewSource(37))
+	for i := 0; i < 500; i++ {
+		from, to := r.Intn(24)-2, r.Intn(24)-2
+		inclusivity := Inclusivity(r.Intn(4))
+		view := m.SubMap(from, to, inclusivity)
+		in := func(k int) bool {
+			lo := k > from || (k == from && inclusivity&IncludeFrom != 0)
+			hi := k < to || (k == to && inclusivity&IncludeTo != 0)
+			return lo && hi
+		}
+		var expected []int
+		for k := 0; k < 20; k += 2 {
+			if in(k) {
+				expected = append(expected, k)
+			}
+		}
+		assert.Equal(t, expected, collectKeys(view))
+		assert.Equal(t, len(expected), view.Len())
+
+		e, ok := view.FirstEntry()
+		assert.Equal(t, len(expected) > 0, ok)
+		if ok {
+			assert.Equal(t, expected[0], e.Key)
+		}
+		e, ok = view.LastEntry()
+		assert.Equal(t, len(expected) > 0, ok)
+		if ok {
+			assert.Equal(t, expected[len(expected)-1], e.Key)
+		}
+		for k := -3; k < 23; k++ {
+			var floor, ceiling, lower, higher []int
+			for _, x := range expected {
+				if x <= k {
+					floor = append(floor, x)
+				}
+				if x < k {
+					lower = append(lower, x)
+				}
+				if x >= k {
+					ceiling = append(ceiling, x)
+				}
+				if x > k {
+					higher = append(higher, x)
+				}
+			}
+			check := func(name string, e int, ok bool, candidates []int, last bool) {
+				assert.Equal(t, len(candidates) > 0, ok, "%v(%v) of [%v, %v] %v", name, k, from, to, inclusivity)
+				if ok && len(candidates) > 0 {
+					want := candidates[0]
+					if last {
+						want = candidates[len(candidates)-1]
+					}
+					assert.Equal(t, want, e, "%v(%v) of [%v, %v] %v", name, k, from, to, inclusivity)
+				}
+			}
+			e, ok = view.FloorEntry(k)
+			check("FloorEntry", e.Key, ok, floor, true)
+			e, ok = view.LowerEntry(k)
+			check("LowerEntry", e.Key, ok, lower, true)
+			e, ok = view.CeilingEntry(k)
+			check("CeilingEntry", e.Key, ok, ceiling, false)
+			e, ok = view.HigherEntry(k)
+			check("HigherEntry", e.Key, ok, higher, false)
+			_, ok = view.Get(k)
+			assert.Equal(t, in(k) && k%2 == 0 && k >= 0 && k < 20, ok)
+		}
+	}
+}
+
+func TestViewWrite(t *testing.T) {
+	m := NewMap[int, string]()
+	for k := 0; k < 10; k++ {
+		m.Put(k, "")
+	}
+	head := m.HeadMap(5)
+	tail := m.TailMap(5)
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, collectKeys(head))
+	assert.Equal(t, []int{5, 6, 7, 8, 9}, collectKeys(tail))
+
+	assert.Panics(t, func() { head.Put(5, "") })
+	assert.Panics(t, func() { tail.PutIfAbsent(4, "") })
+	_, ok := head.Delete(7)
+	assert.False(t, ok)
+	assert.Equal(t, 10, m.Len())
+
+	head.Put(-1, "a")
+	v, ok := m.Get(-1)
+	assert.True(t, ok)
+	assert.Equal(t, "a", v)
+	_, ok = tail.Delete(9)
+	assert.True(t, ok)
+	assert.Equal(t, 10, m.Len())
+
+	sub := tail.SubMap(0, 7, ExcludeBoth)
+	assert.Equal(t, []int{5, 6}, collectKeys(sub))
+	sub = head.SubMap(3, 100, IncludeBoth)
+	assert.Equal(t, []int{3, 4}, collectKeys(sub))
+	assert.Panics(t, func() { sub.Put(5, "") })
+	sub = m.SubMap(2, 4, IncludeFrom).SubMap(2, 4, IncludeBoth)
+	assert.Equal(t, []int{2, 3}, collectKeys(sub))
+
+	e, ok := tail.PollFirst()
+	assert.True(t, ok)
+	assert.Equal(t, 5, e.Key)
+	e, ok = head.PollLast()
+	assert.True(t, ok)
+	assert.Equal(t, 4, e.Key)
+
+	head.Clear()
+	assert.Equal(t, 0, head.Len())
+	assert.Equal(t, []int{6, 7, 8}, collectKeys(m))
+	m.Put(1, "b")
+	assert.Equal(t, 1, head.Len())
+}
+
+func TestMapRange(t *testing.T) {
+	m := NewMap[int, int]()
+	for k := 0; k < 10; k++ {
+		m.Put(k, k*k)
+	}
+	collect := func(seq func(yield func(int, int) bool)) []int {
+		var keys []int
+		seq(func(k, v int) bool {
+			assert.Equal(t, k*k, v)
+			keys = append(keys, k)
+			return true
+		})
+		return keys
+	}
+	assert.Equal(t, []int{3, 4, 5}, collect(m.Range(bst.Excluded(2), bst.Included(5))))
+	assert.Equal(t, []int{2, 3, 4}, collect(m.Range(bst.Included(2), bst.Excluded(5))))
+	assert.Equal(t, []int{0, 1}, collect(m.Range(bst.Unbounded[int](), bst.Excluded(2))))
+	assert.Equal(t, []int{8, 9}, collect(m.Range(bst.Excluded(7), bst.Unbounded[int]())))
+	assert.Nil(t, collect(m.Range(bst.Excluded(5), bst.Excluded(5))))
+	assert.Equal(t, []int{5}, collect(m.Range(bst.Included(5), bst.Included(5))))
+
+	view := m.SubMap(3, 7, IncludeBoth)
+	assert.Equal(t, []int{3, 4, 5}, collect(view.Range(bst.Unbounded[int](), bst.Excluded(6))))
+	assert.Equal(t, []int{6, 7}, collect(view.Range(bst.Excluded(5), bst.Included(100))))
+	assert.Equal(t, []int{4, 5, 6}, collect(view.Range(bst.Excluded(3), bst.Excluded(7))))
+}
@@ -0,0 +1,104 @@
+package treeset
+
+import (
+	"github.com/Sora233/datastructure/bst/avl"
+)
+
+// The set operations assume that both sets order the elements in the same way,
+// the new sets are ordered by the comparator of the receiver.
+
+func (t *treeSet[T]) Contains(elem T) bool {
+	return t.tree.Exists(elem)
+}
+
+func (t *treeSet[T]) First() (elem T, exists bool) {
+	return t.tree.Min()
+}
+
+func (t *treeSet[T]) Last() (elem T, exists bool) {
+	return t.tree.Max()
+}
+
+func (t *treeSet[T]) Floor(elem T) (res T, exists bool) {
+	return t.tree.FindOrPrev(elem)
+}
+
+func (t *treeSet[T]) Ceiling(elem T) (res T, exists bool) {
+	return t.tree.FindOrNext(elem)
+}
+
+func (t *treeSet[T]) PollFirst() (elem T, exists bool) {
+	if elem, exists = t.tree.Min(); exists {
+		t.tree.Delete(elem)
+		t.onDelete(elem)
+	}
+	return
+}
+
+func (t *treeSet[T]) PollLast() (elem T, exists bool) {
+	if elem, exists = t.tree.Max(); exists {
+		t.tree.Delete(elem)
+		t.onDelete(elem)
+	}
+	return
+}
+
+func (t *treeSet[T]) Union(other TreeSet[T]) TreeSet[T] {
+	res := t.filter(func(T) bool { return true })
+	other.Items()(func(elem T) bool {
+		res.PutIfAbsent(elem)
+		return true
+	})
+	return res
+}
+
+func (t *treeSet[T]) Intersect(other TreeSet[T]) TreeSet[T] {
+	return t.filter(other.Contains)
+}
+
+func (t *treeSet[T]) Difference(other TreeSet[T]) TreeSet[T] {
+	return t.filter(func(elem T) bool {
+		return !other.Contains(elem)
+	})
+}
+
+func (t *treeSet[T]) IsSubsetOf(other TreeSet[T]) bool {
+	if t.Len() > other.Len() {
+		return false
+	}
+	res := true
+	t.tree.Range(func(elem T) bool {
+		res = other.Contains(elem)
+		return res
+	})
+	return res
+}
+
+func (t *treeSet[T]) IsDisjoint(other TreeSet[T]) bool {
+	small, large := TreeSet[T](t), other
+	if small.Len() > large.Len() {
+		small, large = large, small
+	}
+	res := true
+	small.Items()(func(elem T) bool {
+		res = !large.Contains(elem)
+		return res
+	})
+	return res
+}
+
+func (t *treeSet[T]) Equal(other TreeSet[T]) bool {
+	return t.Len() == other.Len() && t.IsSubsetOf(other)
+}
+
+// filter return a new set that contains the elements that f returns true.
+func (t *treeSet[T]) filter(f func(elem T) bool) TreeSet[T] {
+	res := AsSet[T](avl.New[T](t.tree.Comparator()))
+	t.tree.Range(func(elem T) bool {
+		if f(elem) {
+			res.Put(elem)
+		}
+		return true
+	})
+	return res
+}
@@ -0,0 +1,35 @@
+package treeset
+
+import (
+	"github.com/Sora233/datastructure"
+	"github.com/Sora233/datastructure/compare"
+)
+
+// FromSlice create a TreeSet that contains the elements of elems.
+func FromSlice[T compare.Ordered](elems []T) TreeSet[T] {
+	return FromSliceWithCompare(elems, compare.OrderedLessCompareF[T]())
+}
+
+// FromSliceWithCompare create a TreeSet ordered by cmp that contains the elements of elems.
+func FromSliceWithCompare[T any](elems []T, cmp compare.ICompare[T]) TreeSet[T] {
+	res := NewSetWithCompare(cmp)
+	for _, e := range elems {
+		res.Put(e)
+	}
+	return res
+}
+
+// Collect create a TreeSet that contains the elements yielded by seq, such as the Items of another set.
+func Collect[T compare.Ordered](seq datastructure.Seq[T]) TreeSet[T] {
+	return CollectWithCompare(seq, compare.OrderedLessCompareF[T]())
+}
+
+// CollectWithCompare create a TreeSet ordered by cmp that contains the elements yielded by seq.
+func CollectWithCompare[T any](seq datastructure.Seq[T], cmp compare.ICompare[T]) TreeSet[T] {
+	res := NewSetWithCompare(cmp)
+	seq(func(e T) bool {
+		res.Put(e)
+		return true
+	})
+	return res
+}
@@ -0,0 +1,19 @@
+package treeset
+
+import (
+	"testing"
+
+	"github.com/Sora233/datastructure/compare"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvert(t *testing.T) {
+	s := FromSlice([]int{3, 1, 2, 3})
+	assert.Equal(t, []int{1, 2, 3}, s.ToSlice())
+	assert.Equal(t, []int{1, 2, 3}, Collect(s.Items()).ToSlice())
+	assert.Empty(t, NewSet[int]().ToSlice())
+
+	reversed := compare.Reverse(compare.OrderedLessCompareF[int]())
+	assert.Equal(t, []int{3, 2, 1}, FromSliceWithCompare([]int{1, 3, 2}, reversed).ToSlice())
+	assert.Equal(t, []int{3, 2, 1}, CollectWithCompare(s.Items(), reversed).ToSlice())
+}
@@ -0,0 +1,51 @@
+package treeset
+
+// Hooks observes the changes of a TreeSet.
+// Each hook is called after the mutation succeeds, a nil hook is skipped.
+// The hooks must not modify the set.
+type Hooks[T any] struct {
+	// OnInsert is called when elem is inserted.
+	OnInsert func(elem T)
+	// OnReplace is called when the stored old is replaced by the equal elem.
+	OnReplace func(old, elem T)
+	// OnDelete is called when elem is deleted.
+	OnDelete func(elem T)
+	// OnClear is called when the set is cleared.
+	OnClear func()
+}
+
+func (t *treeSet[T]) AddHooks(hooks Hooks[T]) {
+	t.hooks = append(t.hooks, hooks)
+}
+
+func (t *treeSet[T]) onInsert(elem T) {
+	for _, h := range t.hooks {
+		if h.OnInsert != nil {
+			h.OnInsert(elem)
+		}
+	}
+}
+
+func (t *treeSet[T]) onReplace(old, elem T) {
+	for _, h := range t.hooks {
+		if h.OnReplace != nil {
+			h.OnReplace(old, elem)
+		}
+	}
+}
+
+func (t *treeSet[T]) onDelete(elem T) {
+	for _, h := range t.hooks {
+		if h.OnDelete != nil {
+			h.OnDelete(elem)
+		}
+	}
+}
+
+func (t *treeSet[T]) onClear() {
+	for _, h := range t.hooks {
+		if h.OnClear != nil {
+			h.OnClear()
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package treeset
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// MarshalJSON encodes the set as a JSON array in ascending order.
+func (t *treeSet[T]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	buf.WriteByte('[')
+	t.Items()(func(elem T) bool {
+		var b []byte
+		if b, err = json.Marshal(elem); err != nil {
+			return false
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(b)
+		return true
+	})
+	buf.WriteByte(']')
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes a JSON array into the set.
+// The decoded elements are added to the set, existing elements are overwritten.
+func (t *treeSet[T]) UnmarshalJSON(data []byte) error {
+	var elems []T
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return err
+	}
+	for _, elem := range elems {
+		t.Put(elem)
+	}
+	return nil
+}
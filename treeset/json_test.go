@@ -0,0 +1,26 @@
+package treeset
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetJSON(t *testing.T) {
+	s := NewSet[int]()
+	s.Put(3)
+	s.Put(1)
+	s.Put(2)
+	b, err := json.Marshal(s)
+	require.Nil(t, err)
+	assert.Equal(t, `[1,2,3]`, string(b))
+
+	s2 := NewSet[int]()
+	require.Nil(t, json.Unmarshal([]byte(`[5,4,5]`), s2))
+	assert.Equal(t, 2, s2.Len())
+	b, err = json.Marshal(s2)
+	require.Nil(t, err)
+	assert.Equal(t, `[4,5]`, string(b))
+}
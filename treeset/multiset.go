@@ -0,0 +1,112 @@
+package treeset
+
+import (
+	"github.com/Sora233/datastructure"
+	"github.com/Sora233/datastructure/bst"
+	"github.com/Sora233/datastructure/bst/avl"
+	"github.com/Sora233/datastructure/compare"
+)
+
+// MultiSet is a sorted collection that keeps the number of occurrences of each element.
+// It is not safe for concurrent use.
+type MultiSet[T any] interface {
+	// Add adds n occurrences of elem, and return the count of elem after adding.
+	Add(elem T, n uint) (count int)
+	// Remove removes at most n occurrences of elem, and return the number of removed occurrences.
+	Remove(elem T, n uint) (removed int)
+	// Count return the number of occurrences of elem.
+	Count(elem T) int
+	// DistinctLen return the number of distinct elements.
+	DistinctLen() int
+	// TotalLen return the number of occurrences of all elements.
+	TotalLen() int
+	// Rank return the number of occurrences of the elements that are smaller than elem, plus one.
+	Rank(elem T) int
+	// Nth return the element that has the rank-th value, every occurrence is counted.
+	// The minimum element has rank 1.
+	Nth(rank int) (elem T, exists bool)
+	Clear()
+	// Items iterate over the distinct elements in ascending order with their counts.
+	Items() datastructure.Seq2[T, int]
+}
+
+type multiSet[T any] struct {
+	tree     bst.MultisetTree[T]
+	distinct int
+}
+
+func (t *multiSet[T]) Add(elem T, n uint) (count int) {
+	count, inserted := t.tree.Add(elem, int(n))
+	if inserted {
+		t.distinct++
+	}
+	return
+}
+
+func (t *multiSet[T]) Remove(elem T, n uint) (removed int) {
+	removed, deleted := t.tree.Remove(elem, int(n))
+	if deleted {
+		t.distinct--
+	}
+	return
+}
+
+func (t *multiSet[T]) Count(elem T) int {
+	return t.tree.Count(elem)
+}
+
+func (t *multiSet[T]) DistinctLen() int {
+	return t.distinct
+}
+
+func (t *multiSet[T]) TotalLen() int {
+	return t.tree.Size()
+}
+
+func (t *multiSet[T]) Rank(elem T) int {
+	return t.tree.Rank(elem)
+}
+
+func (t *multiSet[T]) Nth(rank int) (elem T, exists bool) {
+	elem, exists = t.tree.RankNth(rank)
+	return
+}
+
+func (t *multiSet[T]) Clear() {
+	t.tree.Clear()
+	t.distinct = 0
+}
+
+func (t *multiSet[T]) Items() datastructure.Seq2[T, int] {
+	return func(yield func(T, int) bool) {
+		t.tree.RangeCount(yield)
+	}
+}
+
+func NewMultiSet[T compare.Ordered]() MultiSet[T] {
+	return AsMultiSet[T](avl.New[T](compare.OrderedLessCompareF[T](), avl.WithMultiset[T]()))
+}
+
+func NewMultiSetWithLess[T any](less compare.Less[T]) MultiSet[T] {
+	return AsMultiSet[T](avl.New[T](compare.LessF[T](less), avl.WithMultiset[T]()))
+}
+
+func NewMultiSetWithCompare[T any](cmp compare.ICompare[T]) MultiSet[T] {
+	return AsMultiSet[T](avl.New[T](cmp, avl.WithMultiset[T]()))
+}
+
+// AsMultiSet Create a MultiSet base on the MultisetTree
+// The tree should be created in multiset mode, such as avl.WithMultiset and treap.WithMultiset.
+func AsMultiSet[T any](tree bst.MultisetTree[T]) MultiSet[T] {
+	if tree == nil {
+		panic("AsMultiSet: tree is nil")
+	}
+	s := &multiSet[T]{
+		tree: tree,
+	}
+	tree.RangeCount(func(T, int) bool {
+		s.distinct++
+		return true
+	})
+	return s
+}
@@ -0,0 +1,66 @@
+package treeset
+
+import (
+	"testing"
+
+	"github.com/Sora233/datastructure/bst/avl"
+	"github.com/Sora233/datastructure/compare"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiSet(t *testing.T) {
+	s := NewMultiSet[string]()
+	assert.Equal(t, 2, s.Add("b", 2))
+	assert.Equal(t, 1, s.Add("a", 1))
+	assert.Equal(t, 5, s.Add("b", 3))
+	assert.Equal(t, 3, s.Add("c", 3))
+	assert.Equal(t, 3, s.Add("c", 0))
+	assert.Equal(t, 3, s.DistinctLen())
+	assert.Equal(t, 9, s.TotalLen())
+	assert.Equal(t, 5, s.Count("b"))
+	assert.Equal(t, 0, s.Count("d"))
+
+	assert.Equal(t, 1, s.Rank("a"))
+	assert.Equal(t, 2, s.Rank("b"))
+	assert.Equal(t, 7, s.Rank("c"))
+	assert.Equal(t, 10, s.Rank("d"))
+	for rank, expected := range map[int]string{1: "a", 2: "b", 6: "b", 7: "c", 9: "c"} {
+		elem, ok := s.Nth(rank)
+		assert.True(t, ok)
+		assert.Equal(t, expected, elem)
+	}
+	_, ok := s.Nth(10)
+	assert.False(t, ok)
+
+	assert.Equal(t, 2, s.Remove("b", 2))
+	assert.Equal(t, 3, s.Remove("b", 10))
+	assert.Equal(t, 0, s.Remove("b", 1))
+	assert.Equal(t, 0, s.Remove("c", 0))
+	assert.Equal(t, 2, s.DistinctLen())
+	assert.Equal(t, 4, s.TotalLen())
+
+	var elems []string
+	var counts []int
+	s.Items()(func(elem string, count int) bool {
+		elems = append(elems, elem)
+		counts = append(counts, count)
+		return true
+	})
+	assert.Equal(t, []string{"a", "c"}, elems)
+	assert.Equal(t, []int{1, 3}, counts)
+
+	s.Clear()
+	assert.Equal(t, 0, s.DistinctLen())
+	assert.Equal(t, 0, s.TotalLen())
+}
+
+func TestMultiSetWithoutMultisetMode(t *testing.T) {
+	s := AsMultiSet[int](avl.New(compare.OrderedLessCompareF[int]()))
+	assert.Equal(t, 1, s.Add(1, 1))
+	assert.Equal(t, 1, s.Add(1, 1))
+	assert.Equal(t, 1, s.Add(1, 3))
+	assert.Equal(t, 1, s.DistinctLen())
+	assert.Equal(t, 1, s.TotalLen())
+	assert.Equal(t, 1, s.Remove(1, 1))
+	assert.Equal(t, 0, s.DistinctLen())
+}
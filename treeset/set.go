@@ -1,6 +1,7 @@
 package treeset
 
 import (
+	"github.com/Sora233/datastructure"
 	"github.com/Sora233/datastructure/bst"
 	"github.com/Sora233/datastructure/bst/avl"
 	"github.com/Sora233/datastructure/compare"
@@ -13,20 +14,74 @@ type TreeSet[T any] interface {
 	Delete(elem T) (res T, exists bool)
 	Len() int
 	Clear()
-	Items() func(yield func(T) bool)
+	Items() datastructure.Seq[T]
+	// ToSlice return the elements in ascending order.
+	ToSlice() []T
+
+	// Contains return true if elem exists in the set.
+	Contains(elem T) bool
+
+	// First return the minimum element.
+	First() (elem T, exists bool)
+	// Last return the maximum element.
+	Last() (elem T, exists bool)
+	// Floor return the maximum element E that satisfies E <= elem.
+	Floor(elem T) (res T, exists bool)
+	// Ceiling return the minimum element E that satisfies E >= elem.
+	Ceiling(elem T) (res T, exists bool)
+	// PollFirst deletes and return the minimum element.
+	PollFirst() (elem T, exists bool)
+	// PollLast deletes and return the maximum element.
+	PollLast() (elem T, exists bool)
+
+	// AddHooks registers hooks that observe the changes of the set.
+	// The sets returned by the set operations do not inherit the hooks.
+	AddHooks(hooks Hooks[T])
+
+	// Union return a new set that contains the elements in either set.
+	// The elements of the set are preferred over the equal elements of other.
+	Union(other TreeSet[T]) TreeSet[T]
+	// Intersect return a new set that contains the elements in both sets.
+	Intersect(other TreeSet[T]) TreeSet[T]
+	// Difference return a new set that contains the elements in the set but not in other.
+	Difference(other TreeSet[T]) TreeSet[T]
+	// IsSubsetOf return true if every element of the set is in other.
+	IsSubsetOf(other TreeSet[T]) bool
+	// IsDisjoint return true if the sets have no element in common.
+	IsDisjoint(other TreeSet[T]) bool
+	// Equal return true if the sets contain the same elements.
+	Equal(other TreeSet[T]) bool
+
+	// At return the element at index i in ascending order, the index starts from 0.
+	// If i is out of range, return the zero value and false.
+	At(i int) (elem T, exists bool)
+	// IndexOf return the index of elem in ascending order, or -1 if elem does not exist.
+	IndexOf(elem T) int
+	// RangeByIndex iterate over the elements whose index is in [from, to) in ascending order.
+	// The indices are clamped to [0, Len()].
+	RangeByIndex(from, to int) datastructure.Seq[T]
 }
 
 type treeSet[T any] struct {
-	tree bst.BinarySearchTree[T]
+	tree  bst.BinarySearchTree[T]
+	hooks []Hooks[T]
 }
 
 func (t *treeSet[T]) Put(elem T) (old T, replaced bool) {
 	old, replaced = t.tree.Insert(elem)
+	if replaced {
+		t.onReplace(old, elem)
+	} else {
+		t.onInsert(elem)
+	}
 	return
 }
 
 func (t *treeSet[T]) PutIfAbsent(elem T) (success bool) {
 	success = t.tree.InsertOrIgnore(elem)
+	if success {
+		t.onInsert(elem)
+	}
 	return
 }
 
@@ -37,6 +92,9 @@ func (t *treeSet[T]) Get(elem T) (res T, exists bool) {
 
 func (t *treeSet[T]) Delete(elem T) (res T, exists bool) {
 	res, exists = t.tree.Delete(elem)
+	if exists {
+		t.onDelete(res)
+	}
 	return
 }
 
@@ -46,9 +104,10 @@ func (t *treeSet[T]) Len() int {
 
 func (t *treeSet[T]) Clear() {
 	t.tree.Clear()
+	t.onClear()
 }
 
-func (t *treeSet[T]) Items() func(yield func(T) bool) {
+func (t *treeSet[T]) Items() datastructure.Seq[T] {
 	return func(yield func(T) bool) {
 		t.tree.Range(func(e T) bool {
 			return yield(e)
@@ -56,6 +115,50 @@ func (t *treeSet[T]) Items() func(yield func(T) bool) {
 	}
 }
 
+func (t *treeSet[T]) ToSlice() []T {
+	res := make([]T, 0, t.tree.Size())
+	t.tree.Range(func(e T) bool {
+		res = append(res, e)
+		return true
+	})
+	return res
+}
+
+func (t *treeSet[T]) At(i int) (elem T, exists bool) {
+	if i < 0 {
+		return
+	}
+	return t.tree.RankNth(i + 1)
+}
+
+func (t *treeSet[T]) IndexOf(elem T) int {
+	if !t.tree.Exists(elem) {
+		return -1
+	}
+	return t.tree.Rank(elem) - 1
+}
+
+func (t *treeSet[T]) RangeByIndex(from, to int) datastructure.Seq[T] {
+	return func(yield func(T) bool) {
+		lo, hi := from, to
+		if lo < 0 {
+			lo = 0
+		}
+		if n := t.tree.Size(); hi > n {
+			hi = n
+		}
+		if lo >= hi {
+			return
+		}
+		start, _ := t.tree.RankNth(lo + 1)
+		n := hi - lo
+		t.tree.RangeS(start, func(e T) bool {
+			n--
+			return yield(e) && n > 0
+		})
+	}
+}
+
 func NewSet[T compare.Ordered]() TreeSet[T] {
 	return AsSet[T](avl.New[T](compare.OrderedLessCompareF[T]()))
 }
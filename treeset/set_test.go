@@ -0,0 +1,160 @@
+package treeset
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetPosition(t *testing.T) {
+	s := NewSet[string]()
+	for _, e := range []string{"d", "b", "a", "c", "e"} {
+		s.Put(e)
+	}
+	e, ok := s.At(0)
+	assert.True(t, ok)
+	assert.Equal(t, "a", e)
+	e, ok = s.At(4)
+	assert.True(t, ok)
+	assert.Equal(t, "e", e)
+	_, ok = s.At(5)
+	assert.False(t, ok)
+	_, ok = s.At(-1)
+	assert.False(t, ok)
+	assert.Equal(t, 2, s.IndexOf("c"))
+	assert.Equal(t, -1, s.IndexOf("x"))
+
+	var elems []string
+	s.RangeByIndex(1, 3)(func(e string) bool {
+		elems = append(elems, e)
+		return true
+	})
+	assert.Equal(t, []string{"b", "c"}, elems)
+	elems = nil
+	s.RangeByIndex(3, 10)(func(e string) bool {
+		elems = append(elems, e)
+		return true
+	})
+	assert.Equal(t, []string{"d", "e"}, elems)
+	s.RangeByIndex(4, 2)(func(string) bool { panic("empty range") })
+
+	// the iterator clamps the indexes on every run
+	seq := s.RangeByIndex(3, 100)
+	elems = nil
+	seq(func(e string) bool {
+		elems = append(elems, e)
+		return true
+	})
+	assert.Equal(t, []string{"d", "e"}, elems)
+	s.Put("f")
+	elems = nil
+	seq(func(e string) bool {
+		elems = append(elems, e)
+		return true
+	})
+	assert.Equal(t, []string{"d", "e", "f"}, elems)
+}
+
+func setOf(elems ...int) TreeSet[int] {
+	s := NewSet[int]()
+	for _, e := range elems {
+		s.Put(e)
+	}
+	return s
+}
+
+func elemsOf(s TreeSet[int]) []int {
+	res := []int{}
+	s.Items()(func(e int) bool {
+		res = append(res, e)
+		return true
+	})
+	return res
+}
+
+func TestSetAlgebra(t *testing.T) {
+	a := setOf(1, 2, 3, 4)
+	b := setOf(3, 4, 5)
+	c := setOf(7, 8)
+
+	assert.True(t, a.Contains(2))
+	assert.False(t, a.Contains(5))
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, elemsOf(a.Union(b)))
+	assert.Equal(t, []int{3, 4}, elemsOf(a.Intersect(b)))
+	assert.Equal(t, []int{1, 2}, elemsOf(a.Difference(b)))
+	assert.Equal(t, []int{5}, elemsOf(b.Difference(a)))
+	assert.Equal(t, []int{}, elemsOf(a.Intersect(c)))
+	assert.Equal(t, []int{1, 2, 3, 4}, elemsOf(a), "operands are not modified")
+
+	assert.True(t, setOf(3, 4).IsSubsetOf(a))
+	assert.True(t, setOf().IsSubsetOf(a))
+	assert.False(t, b.IsSubsetOf(a))
+	assert.False(t, a.IsSubsetOf(setOf(1, 2)))
+	assert.True(t, a.IsDisjoint(c))
+	assert.True(t, c.IsDisjoint(setOf()))
+	assert.False(t, a.IsDisjoint(b))
+	assert.True(t, a.Equal(setOf(4, 3, 2, 1)))
+	assert.False(t, a.Equal(setOf(1, 2, 3, 5)))
+	assert.False(t, a.Equal(b))
+
+	reversed := NewSetWithLess[int](func(a, b int) bool { return a > b })
+	reversed.Put(1)
+	reversed.Put(9)
+	assert.Equal(t, []int{9, 3, 1}, elemsOf(reversed.Union(setOf(3))))
+}
+
+func TestSetNavigation(t *testing.T) {
+	s := setOf()
+	_, ok := s.First()
+	assert.False(t, ok)
+	_, ok = s.PollFirst()
+	assert.False(t, ok)
+
+	s = setOf(10, 20, 30)
+	e, _ := s.First()
+	assert.Equal(t, 10, e)
+	e, _ = s.Last()
+	assert.Equal(t, 30, e)
+	e, _ = s.Floor(25)
+	assert.Equal(t, 20, e)
+	e, _ = s.Floor(20)
+	assert.Equal(t, 20, e)
+	_, ok = s.Floor(5)
+	assert.False(t, ok)
+	e, _ = s.Ceiling(25)
+	assert.Equal(t, 30, e)
+	_, ok = s.Ceiling(31)
+	assert.False(t, ok)
+
+	e, ok = s.PollFirst()
+	assert.True(t, ok)
+	assert.Equal(t, 10, e)
+	e, ok = s.PollLast()
+	assert.True(t, ok)
+	assert.Equal(t, 30, e)
+	assert.Equal(t, []int{20}, elemsOf(s))
+}
+
+func TestSetHooks(t *testing.T) {
+	s := setOf(1)
+	var log []string
+	s.AddHooks(Hooks[int]{
+		OnInsert:  func(e int) { log = append(log, fmt.Sprint("insert ", e)) },
+		OnReplace: func(old, e int) { log = append(log, fmt.Sprint("replace ", e)) },
+		OnDelete:  func(e int) { log = append(log, fmt.Sprint("delete ", e)) },
+		OnClear:   func() { log = append(log, "clear") },
+	})
+	s.Put(1)
+	s.Put(2)
+	s.PutIfAbsent(2)
+	s.PutIfAbsent(3)
+	s.Delete(4)
+	s.Delete(2)
+	s.Put(0)
+	s.PollFirst()
+	s.PollLast()
+	s.Union(setOf(9)).Put(10)
+	s.Clear()
+	assert.Equal(t, []string{"replace 1", "insert 2", "insert 3", "delete 2", "insert 0", "delete 0", "delete 3", "clear"}, log)
+}